@@ -12,6 +12,8 @@
 //      Example: myVar, userName
 // 5. Underscore (_) is commonly used as the blank identifier
 //    to ignore values that are not needed.
+// 6. Initialisms keep one case throughout: userID, HTTPClient, parseJSON
+//    (not userId, HttpClient, parseJson).
 
 package main
 
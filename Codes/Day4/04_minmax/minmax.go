@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"math"
+
+	"github.com/ALS240/GoTrainings/pkg/checked"
 )
 
 func main() {
@@ -30,4 +32,12 @@ func main() {
 	fmt.Printf("\nPractical example:\n")
 	fmt.Printf("maxInt8 + 1 = %d (overflow!)\n", maxInt8+1)
 	fmt.Printf("minInt8 - 1 = %d (underflow!)\n", minInt8-1)
+
+	// Opting in to safe arithmetic with pkg/checked
+	fmt.Printf("\nWith pkg/checked:\n")
+	if _, err := checked.Add(maxInt8, 1); err != nil {
+		fmt.Println("checked.Add(maxInt8, 1):", err)
+	}
+	fmt.Printf("checked.SaturatingAdd(maxInt8, 1) = %d (clamped)\n", checked.SaturatingAdd(maxInt8, 1))
+	fmt.Printf("checked.SaturatingSub(minInt8, 1) = %d (clamped)\n", checked.SaturatingSub(minInt8, 1))
 }
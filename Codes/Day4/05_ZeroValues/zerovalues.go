@@ -1,6 +1,11 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ALS240/GoTrainings/pkg/zerovalue"
+)
 
 /*
 ZERO VALUES IN GO
@@ -72,7 +77,7 @@ func main() {
 
 	// Function: zero value is nil (cannot be called)
 	var fn func() // nil
-	fmt.Printf("function:  %#v\t// nil - cannot be called until assigned a function\n", fn)
+	fmt.Printf("function:  fn == nil is %t\t// nil - cannot be called until assigned a function\n", fn == nil)
 
 	// ========== AGGREGATE TYPES ==========
 	fmt.Println("\n--- AGGREGATE TYPES ---")
@@ -166,6 +171,23 @@ func main() {
 	fmt.Printf("   p2: %+v (zero values)\n", p2)
 	fmt.Printf("   p3: %+v (pointer to zero-valued struct)\n", *p3)
 
+	// 6. USABLE AS-IS?
+	fmt.Println("\n6. Is the Zero Value Usable As-Is?")
+	fmt.Println("   pkg/zerovalue walks a type and tells which parts need initialising:")
+
+	// A nil slice accepts append, a nil map panics on write. One nil map
+	// makes the whole struct unusable until it is made.
+	type Registry struct {
+		count int
+		names []string
+		byID  map[int]string
+	}
+	var tree strings.Builder
+	zerovalue.Describe(Registry{}).WriteTree(&tree)
+	for _, line := range strings.Split(strings.TrimSpace(tree.String()), "\n") {
+		fmt.Println("   " + line)
+	}
+
 	fmt.Println("\n=== KEY TAKEAWAYS ===")
 	fmt.Println("1. Zero values make Go memory-safe: no undefined behavior")
 	fmt.Println("2. Reference types are nil by default (must initialize before use)")
@@ -193,6 +215,8 @@ ADDITIONAL NOTES:
 2. Common Pitfalls:
    - nil maps/slices: need to check before use
    - nil pointer dereference: always check pointers
+   - go run ./cmd/lessonvet ./... reports writes to maps that are never
+     made and dereferences of pointers that are never assigned
    - Zero value might not be the desired initial state
 
 3. Best Practices:
@@ -3,7 +3,8 @@ package main
 import (
 	"fmt"
 	"strings"
-	"time"
+
+	"github.com/ALS240/GoTrainings/pkg/pool"
 )
 
 /*
@@ -91,6 +92,7 @@ WHAT WILL ESCAPE:
 
 // Example 2.1: Returning address (Classic escape)
 func createCounter() *int {
+	//escape:heap count
 	count := 0    // HEAP: Will escape!
 	return &count // 🚨 Returning "where I live" address
 	// After function ends, count must still exist
@@ -100,6 +102,7 @@ func createCounter() *int {
 func createPointers() []*int {
 	var pointers []*int
 	for i := 0; i < 3; i++ {
+		//escape:heap value
 		value := i // HEAP: Escapes! Pointer stored in slice
 		pointers = append(pointers, &value)
 	}
@@ -109,6 +112,7 @@ func createPointers() []*int {
 // Example 2.3: Variable captured by closure
 func makeMultiplier(factor int) func(int) int {
 	multiplier := factor // HEAP: Captured by closure below
+	//escape:heap func literal
 	return func(x int) int {
 		return x * multiplier // multiplier must survive
 	}
@@ -117,6 +121,7 @@ func makeMultiplier(factor int) func(int) int {
 // Example 2.4: Used in goroutine
 func startBackgroundTask() {
 	taskID := 100 // HEAP: Used in goroutine
+	//escape:heap func literal
 	go func() {
 		fmt.Printf("Processing task %d\n", taskID)
 		// taskID might be used after startBackgroundTask returns
@@ -126,11 +131,13 @@ func startBackgroundTask() {
 // Example 2.5: Interface causes escape
 func printValue(value int) {
 	// Even this simple print causes escape!
+	//escape:heap value
 	fmt.Println(value) // HEAP: fmt.Println takes interface{}
 }
 
 // Example 2.6: Large allocation
 func processLargeData() []byte {
+	//escape:heap
 	data := make([]byte, 1024*1024) // HEAP: 1MB is too big for stack
 	// Large allocations usually go to heap
 	return data
@@ -157,6 +164,7 @@ Common gray areas:
 func createSlice(size int) []int {
 	// Gray area: Small slices might stay on stack
 	// Large slices definitely go to heap
+	//escape:heap
 	slice := make([]int, size)
 
 	// If we return it, likely heap
@@ -170,6 +178,7 @@ func createSlice(size int) []int {
 
 // Example 3.2: Map - usually heap
 func createMap() map[string]int {
+	//escape:heap
 	m := make(map[string]int) // Usually HEAP
 	m["a"] = 1
 	m["b"] = 2
@@ -187,7 +196,8 @@ type MixedStruct struct {
 
 func createMixedStruct() MixedStruct {
 	s := MixedStruct{
-		Name: "Test",          // String - might escape
+		Name: "Test", // String - might escape
+		//escape:heap
 		Data: make([]int, 10), // Slice - probably escapes
 		Ref:  nil,
 	}
@@ -200,6 +210,7 @@ func createMixedStruct() MixedStruct {
 func createGreeter() func() string {
 	name := "Alice" // Gray area: Might escape if closure escapes
 
+	//escape:heap func literal
 	greeter := func() string {
 		return "Hello, " + name
 	}
@@ -212,6 +223,8 @@ func createGreeter() func() string {
 // ============================================================================
 
 // Pattern 1: When you control allocation
+//
+//escape:stack data
 func processDataEfficient(data []byte) {
 	// Try to keep things on stack
 	buffer := [256]byte{} // Small fixed array - STACK
@@ -231,29 +244,27 @@ func getUserName() string {
 
 func getUserNamePtr() *string {
 	// This forces heap allocation!
+	//escape:heap name
 	name := "John Doe"
 	return &name // 🚨 Don't do this unless you need to
 }
 
 // Pattern 3: Reusing to avoid allocations
-var bufferPool = make(chan []byte, 10) // Shared pool
+// A bounded free list: getBuffer takes an idle buffer or allocates one,
+// returnBuffer hands it back or lets the GC have it when the pool is full.
+// pkg/pool is this pattern as a package you can import, with size classes
+// and hit/miss/drop counters (bufferPool.Stats()).
+var bufferPool = pool.NewSlices(pool.SliceOptions[byte]{
+	Sizes:    []int{1024}, // one class: 1 KB buffers
+	Capacity: 10,          // keep at most 10 idle buffers
+})
 
 func getBuffer() []byte {
-	select {
-	case b := <-bufferPool:
-		return b // Reuse existing buffer
-	default:
-		return make([]byte, 1024) // New allocation (heap)
-	}
+	return bufferPool.Get(1024) // Reuse existing buffer, or allocate (heap)
 }
 
 func returnBuffer(b []byte) {
-	select {
-	case bufferPool <- b:
-		// Returned to pool
-	default:
-		// Pool full, buffer will be garbage collected
-	}
+	bufferPool.Put(b) // Returned to pool, or dropped if the pool is full
 }
 
 // ============================================================================
@@ -288,24 +299,18 @@ func benchmarkExample() {
 	// This shows why escape analysis matters
 	fmt.Println("\n=== PERFORMANCE IMPACT ===")
 
-	start := time.Now()
-	for i := 0; i < 1000000; i++ {
-		// Stack allocation (fast)
-		_ = addNumbers(i, i+1)
-	}
-	stackTime := time.Since(start)
-
-	start = time.Now()
-	for i := 0; i < 1000000; i++ {
-		// Heap allocation (slower)
-		ptr := createCounter()
-		_ = ptr
-	}
-	heapTime := time.Since(start)
-
-	fmt.Printf("Stack operations: %v\n", stackTime)
-	fmt.Printf("Heap operations:  %v\n", heapTime)
-	fmt.Printf("Heap is %.1fx slower\n", float64(heapTime)/float64(stackTime))
+	// Timing addNumbers in a loop with time.Now proves nothing: the
+	// compiler inlines it, sees the result is thrown away and deletes the
+	// loop. Real numbers come from the benchmarks in escape_test.go, which
+	// keep every result alive and count allocations.
+	fmt.Println("Measure, don't guess. Benchmarks live in escape_test.go:")
+	fmt.Println("  go test -run '^$' -bench . -benchmem ./Codes/Day4/07_EscapeAnalysis")
+	fmt.Println("Or as a stack vs heap comparison table:")
+	fmt.Println("  go run ./cmd/escapebench day4/escape")
+	fmt.Println("Read the columns:")
+	fmt.Println("  • ns/op     - time per call")
+	fmt.Println("  • B/op      - bytes allocated on the heap per call")
+	fmt.Println("  • allocs/op - heap allocations per call (0 = stayed on stack)")
 }
 
 // ============================================================================
@@ -334,6 +339,7 @@ func main() {
 
 	// 3. Gray area examples
 	fmt.Println("\n3. GRAY AREA (Compiler decides):")
+	//escape:stack make([]int, 5)
 	smallSlice := createSlice(5)
 	largeSlice := createSlice(5000)
 	fmt.Printf("   Small slice (%d items): compiler might optimize\n", len(smallSlice))
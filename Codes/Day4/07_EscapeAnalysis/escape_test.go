@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Run with:
+//
+//	go test -run '^$' -bench . -benchmem ./Codes/Day4/07_EscapeAnalysis
+//	go run ./cmd/escapebench day4/escape   (same numbers as a comparison table)
+//
+// Every result is stored in a package-level sink. Without that the
+// compiler may inline the call, see the result is never used and delete
+// the work being measured, which is exactly what the old hand-timed loop
+// in benchmarkExample suffered from.
+var (
+	sinkInt     int
+	sinkFloat   float64
+	sinkPtr     *int
+	sinkPtrs    []*int
+	sinkFunc    func(int) int
+	sinkGreeter func() string
+	sinkString  string
+	sinkStrPtr  *string
+	sinkMap     map[string]int
+	sinkInts    []int
+	sinkBytes   []byte
+	sinkPoint   Point
+	sinkMixed   MixedStruct
+)
+
+// Section 1 against section 2: the same work returning a value or a pointer.
+
+func BenchmarkStackVsHeap(b *testing.B) {
+	b.Run("addNumbers", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkInt = addNumbers(i, i+1)
+		}
+	})
+	b.Run("createCounter", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkPtr = createCounter()
+		}
+	})
+}
+
+func BenchmarkValueVsPointer(b *testing.B) {
+	b.Run("getUserName", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkString = getUserName()
+		}
+	})
+	b.Run("getUserNamePtr", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkStrPtr = getUserNamePtr()
+		}
+	})
+}
+
+func BenchmarkStack(b *testing.B) {
+	b.Run("calculateTotal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkFloat = calculateTotal(float64(i), 0.08)
+		}
+	})
+	b.Run("movePoint", func(b *testing.B) {
+		b.ReportAllocs()
+		p := Point{X: 1, Y: 2}
+		for i := 0; i < b.N; i++ {
+			sinkPoint = movePoint(p, i, i)
+		}
+	})
+	b.Run("processCoordinates", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			processCoordinates()
+		}
+	})
+}
+
+func BenchmarkCreatePointers(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sinkPtrs = createPointers()
+	}
+}
+
+func BenchmarkClosure(b *testing.B) {
+	b.Run("makeMultiplier", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkFunc = makeMultiplier(i)
+		}
+	})
+	b.Run("createGreeter", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkGreeter = createGreeter()
+		}
+	})
+}
+
+func BenchmarkCreateMap(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sinkMap = createMap()
+	}
+}
+
+func BenchmarkCreateSlice(b *testing.B) {
+	for _, size := range []int{5, 5000} {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sinkInts = createSlice(size)
+			}
+		})
+	}
+}
+
+func BenchmarkCreateMixedStruct(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sinkMixed = createMixedStruct()
+	}
+}
+
+func BenchmarkLargeData(b *testing.B) {
+	b.Run("processLargeData", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkBytes = processLargeData()
+		}
+	})
+	b.Run("processDataEfficient", func(b *testing.B) {
+		b.ReportAllocs()
+		data := make([]byte, 1024)
+		for i := 0; i < b.N; i++ {
+			processDataEfficient(data)
+		}
+	})
+}
+
+// getBuffer/returnBuffer recycle 1 KB buffers through bufferPool, a
+// pkg/pool size-class pool, instead of allocating a fresh one each time.
+func BenchmarkBufferReuse(b *testing.B) {
+	b.Run("make", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkBytes = make([]byte, 1024)
+		}
+	})
+	b.Run("getBuffer-returnBuffer", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf := getBuffer()
+			sinkBytes = buf
+			returnBuffer(buf)
+		}
+	})
+}
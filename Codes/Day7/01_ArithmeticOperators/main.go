@@ -1,6 +1,10 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/ALS240/GoTrainings/pkg/convert"
+)
 
 func main() {
 	fmt.Println("Arithmetic Operators in Go")
@@ -59,6 +63,16 @@ func main() {
 	result2 := intValue + int(floatValue) // Truncates float
 	fmt.Printf("int + int(float) = %d\n", result2)
 
+	// pkg/convert makes the lost 0.5 an error, or the rounding explicit
+	if _, err := convert.Convert[int](floatValue); err != nil {
+		fmt.Println("Convert:", err)
+	}
+	if rounded, err := convert.Round[int](floatValue, convert.HalfEven); err != nil {
+		fmt.Println("Round:", err)
+	} else {
+		fmt.Printf("int + Round(float, HalfEven) = %d\n", intValue+rounded)
+	}
+
 	// Increment and Decrement operators
 	fmt.Println("\n--- Increment and Decrement ---")
 
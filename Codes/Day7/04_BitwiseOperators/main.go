@@ -1,6 +1,10 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/ALS240/GoTrainings/pkg/flags"
+)
 
 func main() {
 	fmt.Println("BITWISE OPERATORS IN GO")
@@ -154,5 +158,35 @@ func main() {
 	fmt.Println("   User1 can read:", (user1&read) != 0)
 	fmt.Println("   User1 can write:", (user1&write) != 0)
 
+	// Example 5: The same flags with a type
+	fmt.Println("\n5. Typed permission flags (pkg/flags):")
+	perms := Read.Set(Execute) // read | execute
+	fmt.Println("   User1:", perms, "= value", perms.Bits())
+	fmt.Println("   User1 can write:", perms.Has(Write))
+
+	perms = perms.Set(Write) // perms | write
+	fmt.Println("   Grant write:", perms)
+	perms = perms.Clear(Execute) // perms &^ execute
+	fmt.Println("   Revoke execute (&^):", perms)
+	perms = perms.Toggle(Read) // perms ^ read
+	fmt.Println("   Toggle read (^):", perms)
+
+	parsed, err := flags.Parse[Perm]("r-x")
+	if err != nil {
+		fmt.Println("   Parse error:", err)
+	} else {
+		fmt.Println("   Parsed \"r-x\":", parsed.Names(), "= value", parsed.Bits())
+	}
+
 	fmt.Println("\n=== END ===")
 }
+
+// Perm gives the permission bits of example 4 a type and names, using
+// pkg/flags: read is 1, write 2 and execute 4, exactly as before.
+type Perm uint8
+
+var (
+	Read    = flags.Register[Perm]("read", 'r')
+	Write   = flags.Register[Perm]("write", 'w')
+	Execute = flags.Register[Perm]("execute", 'x')
+)
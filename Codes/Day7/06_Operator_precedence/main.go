@@ -1,6 +1,10 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/ALS240/GoTrainings/pkg/expr"
+)
 
 func main() {
 	fmt.Println("OPERATOR PRECEDENCE")
@@ -85,37 +89,28 @@ func main() {
 	fmt.Println("\nPRACTICE - Test Yourself")
 	fmt.Println("========================")
 
-	fmt.Println("\n1. What is 8 - 2 * 3 ?")
-	fmt.Print("Answer: ")
-	fmt.Println(8 - 2*3)
-
-	fmt.Println("\n2. What is (8 - 2) * 3 ?")
-	fmt.Print("Answer: ")
-	fmt.Println((8 - 2) * 3)
-
-	fmt.Println("\n3. What is 12 / 4 + 5 ?")
-	fmt.Print("Answer: ")
-	fmt.Println(12/4 + 5)
-
-	fmt.Println("\n4. What is 12 / (4 + 5) ?")
-	fmt.Print("Answer: ")
-	fmt.Println(12 / (4 + 5))
-
-	fmt.Println("\n5. What is 4 + 5 * 2 == 14 ?")
-	fmt.Print("Answer: ")
-	fmt.Println(4+5*2 == 14)
-
-	fmt.Println("\n6. What is (4 + 5) * 2 == 18 ?")
-	fmt.Print("Answer: ")
-	fmt.Println((4+5)*2 == 18)
-
-	fmt.Println("\n7. What is 10 > 5 && 3 < 4 || 2 == 2 ?")
-	fmt.Print("Answer: ")
-	fmt.Println(10 > 5 && 3 < 4 || 2 == 2)
-
-	fmt.Println("\n8. What is 10 > 5 && (3 < 4 || 2 == 2) ?")
-	fmt.Print("Answer: ")
-	fmt.Println(10 > 5 && (3 < 4 || 2 == 2))
+	// The answers and their explanations are worked out by pkg/expr, which
+	// evaluates an expression the way Go does and records every step.
+	questions := []string{
+		"8 - 2 * 3",
+		"(8 - 2) * 3",
+		"12 / 4 + 5",
+		"12 / (4 + 5)",
+		"4 + 5 * 2 == 14",
+		"(4 + 5) * 2 == 18",
+		"10 > 5 && 3 < 4 || 2 == 2",
+		"10 > 5 && (3 < 4 || 2 == 2)",
+	}
+	for i, q := range questions {
+		fmt.Printf("\n%d. What is %s ?\n", i+1, q)
+		r, err := expr.Eval(q, nil)
+		if err != nil {
+			fmt.Println("Error:", err)
+			continue
+		}
+		fmt.Println("Answer:", r.Value)
+		fmt.Println("Because:", r.Explain())
+	}
 
 	// CHEAT SHEET
 	fmt.Println("\nPRECEDENCE ORDER (Most to Least)")
@@ -0,0 +1,186 @@
+// Command bitview shows an integer operation bit by bit, for the bitwise
+// operator lessons of Day 7.
+//
+// Usage:
+//
+//	bitview [-t types] x op y
+//	bitview [-t types] ^x
+//
+// op is one of & | ^ &^ << >> + -. Operands are Go integer literals (5,
+// -7, 0x2a, 0b1010) and must fit the type; the count of a shift may be
+// any non-negative number. -t names the type, int8 by default; several
+// may be given separated by commas, or "all". For example
+//
+//	bitview -t int8,uint8 ^5
+//
+// shows that the same flipped bits are -6 as an int8 and 250 as a uint8.
+// Quote the expression in shells that treat & | ^ < > specially. An
+// expression may start with a negative number, bitview '-100 >> 2', since
+// no flag name starts with a digit; -- ends the flags in any other case.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ALS240/GoTrainings/pkg/bitview"
+)
+
+func main() {
+	typeNames := flag.String("t", "int8", "integer `types` to show, separated by commas, or all")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: bitview [-t types] [--] 'x op y' | '^x'")
+		fmt.Fprintln(os.Stderr, "  op is one of & | ^ &^ << >> + -; x may be negative: '-128 >> 1'")
+		flag.PrintDefaults()
+	}
+	flag.CommandLine.Parse(endFlags(os.Args[1:]))
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var types []bitview.Type
+	if *typeNames == "all" {
+		types = bitview.Types
+	} else {
+		for _, name := range strings.Split(*typeNames, ",") {
+			t, ok := bitview.TypeNamed(strings.TrimSpace(name))
+			if !ok {
+				fatal(fmt.Errorf("unknown type %q", name))
+			}
+			types = append(types, t)
+		}
+	}
+	x, op, y, err := parse(strings.Join(flag.Args(), " "))
+	if err != nil {
+		fatal(err)
+	}
+
+	for i, t := range types {
+		if i > 0 {
+			fmt.Println()
+		}
+		o, err := apply(t, x, op, y)
+		if err != nil {
+			// With several types, one that cannot hold an operand is
+			// reported and skipped.
+			if len(types) == 1 {
+				fatal(err)
+			}
+			fmt.Printf("%s: %v\n", t.Name, err)
+			continue
+		}
+		if err := o.WriteText(os.Stdout); err != nil {
+			fatal(err)
+		}
+	}
+}
+
+// endFlags inserts -- before the first argument that starts with a
+// negative number, such as '-5 &^ 3', so the flag package does not take it
+// for a flag. A flag's value, as in -t -5, is left alone.
+func endFlags(args []string) []string {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--" || !strings.HasPrefix(a, "-"):
+			return args
+		case len(a) > 1 && a[1] >= '0' && a[1] <= '9':
+			return slices.Insert(slices.Clone(args), i, "--")
+		case !strings.Contains(a, "=") && flag.Lookup(strings.TrimLeft(a, "-")) != nil:
+			i++ // skip the flag's value
+		}
+	}
+	return args
+}
+
+func apply(t bitview.Type, x, op, y string) (bitview.Operation, error) {
+	xv, err := t.Parse(x)
+	if err != nil {
+		return bitview.Operation{}, err
+	}
+	var yv uint64
+	switch op {
+	case "^x":
+	case "<<", ">>":
+		if strings.HasPrefix(y, "-") {
+			return bitview.Operation{}, fmt.Errorf("invalid shift count %s: must not be negative", y)
+		}
+		if yv, err = strconv.ParseUint(y, 0, 64); err != nil {
+			return bitview.Operation{}, fmt.Errorf("invalid shift count %q", y)
+		}
+	default:
+		if yv, err = t.Parse(y); err != nil {
+			return bitview.Operation{}, err
+		}
+	}
+	return bitview.Apply(t, op, xv, yv)
+}
+
+// parse splits an expression into its operands and operator; a unary ^
+// is returned as the operator "^x".
+func parse(expr string) (x, op, y string, err error) {
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	var scanErr error
+	s.Init(fset.AddFile("", -1, len(expr)), []byte(expr), func(_ token.Position, msg string) { scanErr = errors.New(msg) }, 0)
+	var toks []token.Token
+	var lits []string
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF || tok == token.SEMICOLON && lit == "\n" {
+			break
+		}
+		toks, lits = append(toks, tok), append(lits, lit)
+	}
+	if scanErr != nil {
+		return "", "", "", scanErr
+	}
+
+	// operand reads an optionally negated integer literal at toks[i].
+	operand := func(i int) (string, int, bool) {
+		sign := ""
+		if i < len(toks) && toks[i] == token.SUB {
+			sign, i = "-", i+1
+		}
+		if i < len(toks) && toks[i] == token.INT {
+			return sign + lits[i], i + 1, true
+		}
+		return "", i, false
+	}
+	bad := fmt.Errorf("cannot read %q: want x op y or ^x, with op one of & | ^ &^ << >> + -", expr)
+	if len(toks) > 0 && toks[0] == token.XOR {
+		x, i, ok := operand(1)
+		if !ok || i != len(toks) {
+			return "", "", "", bad
+		}
+		return x, "^x", "", nil
+	}
+	x, i, ok := operand(0)
+	if !ok || i >= len(toks) {
+		return "", "", "", bad
+	}
+	switch toks[i] {
+	case token.AND, token.OR, token.XOR, token.AND_NOT, token.SHL, token.SHR, token.ADD, token.SUB:
+		op = toks[i].String()
+	default:
+		return "", "", "", bad
+	}
+	y, j, ok := operand(i + 1)
+	if !ok || j != len(toks) {
+		return "", "", "", bad
+	}
+	return x, op, y, nil
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "bitview:", err)
+	os.Exit(1)
+}
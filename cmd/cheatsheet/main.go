@@ -0,0 +1,55 @@
+// Command cheatsheet prints the data-type cheat sheet for every predeclared
+// Go type: size, zero value and exact and abbreviated limits, all derived
+// from unsafe.Sizeof, the math constants and reflect.Zero.
+//
+// Usage:
+//
+//	cheatsheet [-format text|markdown|csv|json]
+//
+// Sizes of int, uint, uintptr, string and error are those of the
+// architecture the command runs on; use GOARCH=386 go run ./cmd/cheatsheet
+// for a 32-bit sheet.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ALS240/GoTrainings/internal/cheatsheet"
+)
+
+func main() {
+	format := flag.String("format", "text", "output format: text, markdown, csv or json")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: cheatsheet [-format text|markdown|csv|json]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	rows := cheatsheet.Rows()
+	var err error
+	switch *format {
+	case "text":
+		err = cheatsheet.WriteText(os.Stdout, rows)
+	case "markdown", "md":
+		err = cheatsheet.WriteMarkdown(os.Stdout, rows)
+	case "csv":
+		err = cheatsheet.WriteCSV(os.Stdout, rows)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(rows)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cheatsheet:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,88 @@
+// Command compilecheck checks a snippet written for an "observe the error"
+// exercise: it type-checks the snippet and passes when the compile error the
+// exercise asks for occurs, printing the compiler's messages verbatim.
+//
+// Usage:
+//
+//	compilecheck -list
+//	compilecheck -exercise day3/q5 snippet.go
+//	compilecheck -exercise day3/q18 - < snippet.go
+//
+// The snippet may be a whole file, top-level declarations, or bare
+// statements; fmt and other common packages are imported automatically.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ALS240/GoTrainings/internal/compilecheck"
+)
+
+func main() {
+	list := flag.Bool("list", false, "list the exercises")
+	id := flag.String("exercise", "", "exercise to check, e.g. day3/q5")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: compilecheck -list | compilecheck -exercise id file.go|-")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *list {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, e := range compilecheck.Exercises() {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", e.ID, e.Want, e.Prompt)
+		}
+		tw.Flush()
+		return
+	}
+	if *id == "" || flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	ex, ok := compilecheck.Lookup(*id)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "compilecheck: unknown exercise %q (see -list)\n", *id)
+		os.Exit(2)
+	}
+
+	name := flag.Arg(0)
+	var src []byte
+	var err error
+	if name == "-" {
+		name = "snippet.go"
+		src, err = io.ReadAll(os.Stdin)
+	} else {
+		src, err = os.ReadFile(name)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "compilecheck:", err)
+		os.Exit(1)
+	}
+
+	r, err := ex.Check(name, src)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "compilecheck:", err)
+		os.Exit(1)
+	}
+	switch {
+	case r.Passed:
+		fmt.Printf("PASS %s: the snippet fails with the expected compile error (%s)\n", ex.ID, ex.Want)
+	case len(r.Errors) == 0:
+		fmt.Printf("FAIL %s: the snippet compiles, but the exercise expects a compile error (%s)\n", ex.ID, ex.Want)
+	default:
+		fmt.Printf("FAIL %s: the snippet fails, but not with the expected compile error (%s)\n", ex.ID, ex.Want)
+	}
+	if len(r.Errors) > 0 {
+		fmt.Println("\nCompiler says:")
+		for _, d := range r.Errors {
+			fmt.Printf("  %s\n", d)
+		}
+	}
+	if !r.Passed {
+		os.Exit(1)
+	}
+}
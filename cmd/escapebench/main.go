@@ -0,0 +1,78 @@
+// Command escapebench runs a lesson's benchmarks with -benchmem and prints
+// the results as a comparison table: ns/op, B/op and allocs/op for every
+// benchmark, and within each group how each variant compares with the
+// first, e.g. how much slower createCounter (heap) is than addNumbers
+// (stack).
+//
+// Usage:
+//
+//	escapebench [-bench regexp] [-benchtime d] [-json] [lesson or path]
+//	go test -bench . -benchmem ./pkg | escapebench [-json] -
+//
+// The target defaults to the escape analysis lesson. With - the benchmark
+// output is read from standard input instead of being produced.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ALS240/GoTrainings/internal/bench"
+	"github.com/ALS240/GoTrainings/internal/lessons"
+)
+
+func main() {
+	pattern := flag.String("bench", ".", "run only benchmarks matching `regexp`")
+	benchtime := flag.String("benchtime", "", "passed to go test -benchtime, e.g. 200ms or 1000x")
+	asJSON := flag.Bool("json", false, "print the results as JSON")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: escapebench [-bench regexp] [-benchtime d] [-json] [lesson or path | -]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	arg := "day4/escape"
+	if flag.NArg() == 1 {
+		arg = flag.Arg(0)
+	}
+
+	var results []bench.Result
+	var err error
+	if arg == "-" {
+		results, err = bench.Parse(os.Stdin)
+	} else {
+		var dir, target string
+		dir, target, err = lessons.Target(arg)
+		if err == nil {
+			results, err = bench.Run(context.Background(), dir, target, *pattern, *benchtime)
+		}
+	}
+	if err != nil {
+		fatal(err)
+	}
+	if len(results) == 0 {
+		fatal(fmt.Errorf("no benchmark results for %s", arg))
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(results)
+	} else {
+		err = bench.WriteTable(os.Stdout, results)
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "escapebench:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,108 @@
+// Command escapereport runs the compiler's escape analysis on a lesson and
+// prints which variables escape to the heap and why, which parameters leak,
+// and what was inlined.
+//
+// Usage:
+//
+//	escapereport [-format table|source|json] [-all] <lesson or path>
+//	escapereport -verify [-format table|json] <lesson or path>
+//
+// The target is a lesson name understood by gotrain (e.g. day4/escape) or
+// anything go build accepts, such as ./Codes/Day4/07_EscapeAnalysis or a
+// single .go file.
+//
+// With -verify the //escape:heap and //escape:stack claims written in the
+// source are checked against what the current compiler decided, and the
+// command exits with status 1 if any claim no longer holds.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ALS240/GoTrainings/internal/escape"
+	"github.com/ALS240/GoTrainings/internal/lessons"
+)
+
+func main() {
+	format := flag.String("format", "table", "output format: table, source or json")
+	all := flag.Bool("all", false, "include inlined calls and variadic '... argument' lines")
+	verify := flag.Bool("verify", false, "check the //escape: claims in the source against the compiler")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: escapereport [-format table|source|json] [-all] <lesson or path>")
+		fmt.Fprintln(os.Stderr, "       escapereport -verify [-format table|json] <lesson or path>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir, target, err := lessons.Target(flag.Arg(0))
+	if err != nil {
+		fatal(err)
+	}
+	if *verify {
+		verifyClaims(dir, target, *format)
+		return
+	}
+	r, err := escape.Analyze(context.Background(), dir, target)
+	if err != nil {
+		fatal(err)
+	}
+	if !*all {
+		r = r.Filter(escape.Essential)
+	}
+
+	switch *format {
+	case "table":
+		err = r.WriteTable(os.Stdout)
+	case "source":
+		err = r.WriteSource(os.Stdout)
+	case "json":
+		err = r.WriteJSON(os.Stdout)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+func verifyClaims(dir, target, format string) {
+	verdicts, err := escape.VerifyPackage(context.Background(), dir, target)
+	if err != nil {
+		fatal(err)
+	}
+	failed := 0
+	switch format {
+	case "table":
+		failed, err = escape.WriteVerdicts(os.Stdout, verdicts)
+	case "json":
+		for _, v := range verdicts {
+			if !v.OK {
+				failed++
+			}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(verdicts)
+	default:
+		err = fmt.Errorf("-verify: unknown format %q", format)
+	}
+	if err != nil {
+		fatal(err)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "escapereport:", err)
+	os.Exit(1)
+}
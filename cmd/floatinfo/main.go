@@ -0,0 +1,82 @@
+// Command floatinfo shows how a number is stored as float64 and float32:
+// the sign, exponent and mantissa bits, the exact decimal value, the ULP
+// and the neighbouring values, and the error of a float64 -> float32 ->
+// float64 round trip.
+//
+// Usage:
+//
+//	floatinfo value...     e.g. floatinfo 0.1 3.141592653589793 1/3
+//	floatinfo -pitfalls    list classic pitfalls such as 0.1+0.2 != 0.3
+//
+// A value is a decimal literal, a quotient a/b, Inf, -Inf or NaN. Put --
+// before negative values so they are not taken for flags: floatinfo -- -0.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ALS240/GoTrainings/pkg/floatinfo"
+)
+
+func main() {
+	pitfalls := flag.Bool("pitfalls", false, "list classic floating-point pitfalls")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: floatinfo value... | floatinfo -pitfalls")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *pitfalls {
+		writePitfalls()
+		return
+	}
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	for i, arg := range flag.Args() {
+		f64, f32, err := floatinfo.Parse(arg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "floatinfo:", err)
+			os.Exit(1)
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(arg)
+		fmt.Println("  float64")
+		f64.WriteText(os.Stdout, "    ")
+		fmt.Println("  float32")
+		f32.WriteText(os.Stdout, "    ")
+
+		n := floatinfo.Narrow(f64.Float)
+		fmt.Printf("  float64 -> float32 -> float64\n    %v", n.Back)
+		switch {
+		case n.Exact:
+			fmt.Println(" (exact)")
+		case math.IsInf(n.AbsError, 0):
+			fmt.Println(" (too large for float32)")
+		default:
+			fmt.Printf(" (error %+.3g, relative %+.3g)\n", n.AbsError, n.RelError)
+		}
+	}
+}
+
+func writePitfalls() {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, p := range floatinfo.Pitfalls() {
+		mark := "  "
+		if p.Surprising() {
+			mark = "! "
+		}
+		fmt.Fprintf(tw, "%s%s\n", mark, p.Name)
+		fmt.Fprintf(tw, "    \t%s\n", p.Expr)
+		fmt.Fprintf(tw, "    got\t%s\t(expected %s)\n", p.Result, p.Naive)
+		fmt.Fprintf(tw, "    why\t%s\n", p.Explain)
+	}
+	tw.Flush()
+}
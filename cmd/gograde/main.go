@@ -0,0 +1,59 @@
+// Command gograde grades students' copies of an assignment against a hidden
+// test suite and prints a per-question score report.
+//
+// Usage:
+//
+//	gograde [-suite day9] [-json] submission.go...
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ALS240/GoTrainings/internal/grader"
+)
+
+func main() {
+	suiteName := flag.String("suite", "day9", "hidden test suite to run ("+strings.Join(grader.Names(), ", ")+")")
+	asJSON := flag.Bool("json", false, "print the reports as JSON")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: gograde [-suite name] [-json] submission.go...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	suite, ok := grader.Lookup(*suiteName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "gograde: unknown suite %q\n", *suiteName)
+		os.Exit(2)
+	}
+
+	exit := 0
+	var reports []*grader.Report
+	for _, path := range flag.Args() {
+		r, err := suite.Grade(context.Background(), path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gograde: %s: %v\n", path, err)
+			exit = 1
+			continue
+		}
+		reports = append(reports, r)
+		if !*asJSON {
+			r.WriteText(os.Stdout)
+		}
+	}
+	if *asJSON {
+		if err := grader.WriteJSON(os.Stdout, reports); err != nil {
+			fmt.Fprintln(os.Stderr, "gograde:", err)
+			exit = 1
+		}
+	}
+	os.Exit(exit)
+}
@@ -0,0 +1,172 @@
+// Command goplay is a Go prompt for practising the declarations of Day 3
+// and Day 4: var, :=, grouped declarations and redeclaration. Each line is
+// type-checked against everything entered before it and, when it
+// compiles, run; goplay prints the type and value of every name the line
+// declared or assigned, or of the expression it evaluates, and otherwise
+// the compile error.
+//
+// Usage:
+//
+//	goplay [-timeout d]
+//
+// Lines that leave a bracket, brace or parenthesis open are continued on
+// the next line. Besides Go, the prompt accepts:
+//
+//	:scope    list every name declared so far with its type and value
+//	:source   print the program entered so far
+//	:reset    forget everything
+//	:help     list the commands
+//	:quit     leave (so does end of input)
+//
+// Values are printed with %#v. Programs run with the go command, so it
+// must be on the PATH.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ALS240/GoTrainings/internal/playground"
+)
+
+const help = `Enter Go statements, expressions, imports, or func and type declarations.
+  :scope    list every name declared so far
+  :source   print the program entered so far
+  :reset    forget everything
+  :help     show this help
+  :quit     leave
+`
+
+func main() {
+	timeout := flag.Duration("timeout", 10*time.Second, "stop a line that runs longer than `d`")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: goplay [-timeout d]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	s := playground.New()
+	defer s.Close()
+	if err := repl(s, os.Stdin, os.Stdout, *timeout); err != nil {
+		s.Close()
+		fmt.Fprintln(os.Stderr, "goplay:", err)
+		os.Exit(1)
+	}
+}
+
+func repl(s *playground.Session, in io.Reader, out io.Writer, timeout time.Duration) error {
+	sc := bufio.NewScanner(in)
+	fmt.Fprint(out, "goplay: type :help for commands\n>>> ")
+	var input strings.Builder
+	for sc.Scan() {
+		input.WriteString(sc.Text())
+		input.WriteString("\n")
+		if !playground.Complete(input.String()) {
+			fmt.Fprint(out, "... ")
+			continue
+		}
+		line := strings.TrimSpace(input.String())
+		input.Reset()
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		quit, err := eval(ctx, s, line, out)
+		cancel()
+		if err != nil || quit {
+			return err
+		}
+		fmt.Fprint(out, ">>> ")
+	}
+	fmt.Fprintln(out)
+	return sc.Err()
+}
+
+// eval runs one complete input and reports whether it was :quit.
+func eval(ctx context.Context, s *playground.Session, line string, out io.Writer) (quit bool, err error) {
+	switch line {
+	case "":
+		return false, nil
+	case ":quit", ":q", ":exit":
+		return true, nil
+	case ":help", ":h":
+		fmt.Fprint(out, help)
+		return false, nil
+	case ":reset":
+		s.Reset()
+		fmt.Fprintln(out, "scope cleared")
+		return false, nil
+	case ":source":
+		fmt.Fprint(out, s.Source())
+		return false, nil
+	case ":scope":
+		all, err := s.Scope(ctx)
+		if err != nil {
+			return false, err
+		}
+		if len(all) == 0 {
+			fmt.Fprintln(out, "(nothing declared)")
+		}
+		for _, b := range all {
+			fmt.Fprintln(out, describe(b))
+		}
+		return false, nil
+	}
+	if strings.HasPrefix(line, ":") {
+		fmt.Fprintf(out, "unknown command %s; type :help for commands\n", line)
+		return false, nil
+	}
+
+	r, err := s.Eval(ctx, line)
+	if err != nil {
+		return false, err
+	}
+	fmt.Fprint(out, r.Output)
+	if r.Output != "" && !strings.HasSuffix(r.Output, "\n") {
+		fmt.Fprintln(out)
+	}
+	for _, msg := range r.Errors {
+		fmt.Fprintln(out, "error:", msg)
+	}
+	for _, b := range r.Bindings {
+		note := "assigned"
+		if b.New {
+			note = "declared"
+		}
+		if b.Name == "" {
+			fmt.Fprintln(out, describe(b))
+		} else {
+			fmt.Fprintf(out, "%-9s%s\n", note, describe(b))
+		}
+	}
+	return false, nil
+}
+
+// describe prints a binding the way it could be declared, e.g.
+// "var x int = 5", or an expression's value as "int = 5".
+func describe(b playground.Binding) string {
+	switch b.Kind {
+	case "func":
+		return "func " + b.Name + b.Type
+	case "type":
+		return "type " + b.Name + " " + b.Type
+	case "value":
+		return b.Type + " = " + b.Value
+	}
+	if b.Name == "" {
+		return b.Type + " = " + b.Value + " (constant)"
+	}
+	s := b.Kind + " " + b.Name + " " + b.Type
+	if b.Value != "" {
+		s += " = " + b.Value
+	}
+	return s
+}
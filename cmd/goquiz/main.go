@@ -0,0 +1,182 @@
+// Command goquiz extracts the theory questions from an assignment, runs them
+// as an interactive quiz and scores answers against a key.
+//
+// Usage:
+//
+//	goquiz extract [-title t] assignment.go > quiz.json
+//	goquiz take [-key key.json] quiz.json
+//	goquiz score [-key key.json] [-json] quiz.json answers.json
+//	goquiz answers [-quiz quiz.json] [-key key.json] [-json] submission.go
+//
+// An answer key or an answers file is a JSON object mapping question IDs to
+// responses, e.g. {"1": "b", "2": "true"}. The answers command reads a
+// student's "// Answer: x" comments into such a sheet, reports missing or
+// malformed answers, and scores the sheet when given a quiz.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ALS240/GoTrainings/internal/quiz"
+)
+
+const usage = `usage:
+  goquiz extract [-title t] assignment.go          print the comment-block questions as a JSON quiz
+  goquiz take [-key key.json] quiz.json            take a quiz in the terminal
+  goquiz score [-key key.json] [-json] quiz.json answers.json
+                                                   score an answers file against the key
+  goquiz answers [-quiz quiz.json] [-key key.json] [-json] submission.go
+                                                   read "// Answer: x" comments into an answer sheet
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	var err error
+	switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+	case "extract":
+		err = extract(args)
+	case "take":
+		err = take(args)
+	case "score":
+		err = score(args)
+	case "answers":
+		err = answers(args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "goquiz:", err)
+		os.Exit(1)
+	}
+}
+
+func extract(args []string) error {
+	fs := flag.NewFlagSet("extract", flag.ExitOnError)
+	title := fs.String("title", "", "quiz title (default: the file name)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("extract: want exactly one Go file")
+	}
+	q, err := quiz.Extract(fs.Arg(0), nil)
+	if err != nil {
+		return err
+	}
+	if len(q.Questions) == 0 {
+		return fmt.Errorf("extract: no theory questions found in %s", fs.Arg(0))
+	}
+	if *title != "" {
+		q.Title = *title
+	}
+	return q.Write(os.Stdout)
+}
+
+// load reads a quiz and applies the optional external key.
+func load(path, keyPath string) (*quiz.Quiz, error) {
+	q, err := quiz.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if keyPath != "" {
+		key, err := quiz.LoadKey(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := q.ApplyKey(key); err != nil {
+			return nil, err
+		}
+	}
+	return q, nil
+}
+
+func take(args []string) error {
+	fs := flag.NewFlagSet("take", flag.ExitOnError)
+	keyPath := fs.String("key", "", "answer key overriding the answers stored in the quiz")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("take: want exactly one quiz file")
+	}
+	q, err := load(fs.Arg(0), *keyPath)
+	if err != nil {
+		return err
+	}
+	r, err := q.Take(os.Stdin, os.Stdout)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	return r.WriteText(os.Stdout)
+}
+
+func score(args []string) error {
+	fs := flag.NewFlagSet("score", flag.ExitOnError)
+	keyPath := fs.String("key", "", "answer key overriding the answers stored in the quiz")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return errors.New("score: want a quiz file and an answers file")
+	}
+	q, err := load(fs.Arg(0), *keyPath)
+	if err != nil {
+		return err
+	}
+	answers, err := quiz.LoadKey(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	r := q.Score(answers)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	return r.WriteText(os.Stdout)
+}
+
+func answers(args []string) error {
+	fs := flag.NewFlagSet("answers", flag.ExitOnError)
+	quizPath := fs.String("quiz", "", "quiz to score the sheet against")
+	keyPath := fs.String("key", "", "answer key overriding the answers stored in the quiz")
+	asJSON := fs.Bool("json", false, "print the sheet (and result) as JSON")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("answers: want exactly one Go file")
+	}
+	sheet, err := quiz.ExtractAnswers(fs.Arg(0), nil)
+	if err != nil {
+		return err
+	}
+	var r *quiz.Result
+	if *quizPath != "" {
+		q, err := load(*quizPath, *keyPath)
+		if err != nil {
+			return err
+		}
+		r = q.Score(sheet.Answers)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			*quiz.Sheet
+			Result *quiz.Result `json:"result,omitempty"`
+		}{sheet, r})
+	}
+	sheet.WriteDiagnostics(os.Stderr)
+	if r != nil {
+		return r.WriteText(os.Stdout)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sheet.Answers)
+}
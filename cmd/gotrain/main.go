@@ -0,0 +1,155 @@
+// Command gotrain lists, runs and displays the lesson programs under Codes/.
+//
+// Usage:
+//
+//	gotrain list [day]
+//	gotrain run <lesson> [args...]
+//	gotrain show [-width n] <lesson>
+//
+// A lesson is named by day and lesson, e.g. "day7/bitwise", "day7/04" or
+// "day8/loops"; any unambiguous part of the lesson name works.
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ALS240/GoTrainings/internal/lessons"
+)
+
+const usage = `usage:
+  gotrain list [day]                  list lessons and their topics
+  gotrain run <lesson> [args...]      run a lesson
+  gotrain show [-width n] <lesson>    show a lesson's source next to its output
+
+Lessons are named like day7/bitwise, day7/04 or day8/loops.
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	root, err := lessons.Root(".")
+	if err != nil {
+		fatal(err)
+	}
+	all, err := lessons.Index(root)
+	if err != nil {
+		fatal(err)
+	}
+
+	cmd, args := flag.Arg(0), flag.Args()[1:]
+	switch cmd {
+	case "list":
+		err = list(all, args)
+	case "run":
+		err = run(root, all, args)
+	case "show":
+		err = show(root, all, args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.ExitCode())
+		}
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "gotrain:", err)
+	os.Exit(1)
+}
+
+func list(all []*lessons.Lesson, args []string) error {
+	if len(args) > 0 {
+		day := strings.ToLower(args[0])
+		if !strings.HasPrefix(day, "day") {
+			day = "day" + day
+		}
+		var keep []*lessons.Lesson
+		for _, l := range all {
+			if fmt.Sprintf("day%d", l.Day) == day {
+				keep = append(keep, l)
+			}
+		}
+		if len(keep) == 0 {
+			return fmt.Errorf("no lessons for %s", args[0])
+		}
+		all = keep
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	day := 0
+	for _, l := range all {
+		if l.Day != day {
+			if day != 0 {
+				fmt.Fprintln(tw)
+			}
+			fmt.Fprintf(tw, "Day %d\n", l.Day)
+			day = l.Day
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", l.ID, l.Dir, l.Topic)
+	}
+	return tw.Flush()
+}
+
+func run(root string, all []*lessons.Lesson, args []string) error {
+	if len(args) == 0 {
+		return errors.New("run: missing lesson name")
+	}
+	l, err := lessons.Find(all, args[0])
+	if err != nil {
+		return err
+	}
+	cmd := l.Command(context.Background(), root, args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+func show(root string, all []*lessons.Lesson, args []string) error {
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	width := fs.Int("width", terminalWidth(), "total output width in columns")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return errors.New("show: missing lesson name")
+	}
+	l, err := lessons.Find(all, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	src, err := l.Source(root)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	runErr := l.Run(context.Background(), root, &out, &out)
+
+	fmt.Printf("%s — %s (%s)\n\n", l.ID, l.Topic, l.Dir)
+	sideBySide(os.Stdout, lines(src), lines(out.Bytes()), *width)
+	return runErr
+}
+
+// terminalWidth honours $COLUMNS and falls back to a width that fits two
+// 80-column panes.
+func terminalWidth() int {
+	var n int
+	if _, err := fmt.Sscan(os.Getenv("COLUMNS"), &n); err == nil && n > 40 {
+		return n
+	}
+	return 160
+}
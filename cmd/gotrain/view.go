@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const tabWidth = 4
+
+// lines splits b into lines with tabs expanded, dropping a final newline.
+func lines(b []byte) []string {
+	s := strings.TrimRight(string(b), "\n")
+	if s == "" {
+		return nil
+	}
+	out := strings.Split(s, "\n")
+	for i, l := range out {
+		out[i] = expandTabs(strings.TrimRight(l, "\r"))
+	}
+	return out
+}
+
+func expandTabs(s string) string {
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+	var b strings.Builder
+	col := 0
+	for _, r := range s {
+		if r == '\t' {
+			n := tabWidth - col%tabWidth
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		b.WriteRune(r)
+		col++
+	}
+	return b.String()
+}
+
+// sideBySide writes left (numbered source) and right (program output) as
+// two columns separated by a rule. Lines that do not fit are cut with "…".
+func sideBySide(w io.Writer, left, right []string, width int) {
+	const gutter = 5 // "NNN  "
+	pane := (width - 3) / 2
+	if pane < 20 {
+		pane = 20
+	}
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+
+	fmt.Fprintf(bw, "%s | %s\n", pad("SOURCE", pane), "OUTPUT")
+	fmt.Fprintf(bw, "%s-+-%s\n", strings.Repeat("-", pane), strings.Repeat("-", pane))
+	for i := 0; i < len(left) || i < len(right); i++ {
+		var l, r string
+		if i < len(left) {
+			l = fmt.Sprintf("%3d  %s", i+1, fit(left[i], pane-gutter))
+		}
+		if i < len(right) {
+			r = fit(right[i], pane)
+		}
+		fmt.Fprintln(bw, strings.TrimRight(pad(l, pane)+" | "+r, " "))
+	}
+}
+
+// fit cuts s to at most n runes.
+func fit(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n-1]) + "…"
+}
+
+// pad right-pads s with spaces to n runes.
+func pad(s string, n int) string {
+	if c := utf8.RuneCountInString(s); c < n {
+		return s + strings.Repeat(" ", n-c)
+	}
+	return s
+}
@@ -0,0 +1,30 @@
+// Command lessonvet runs the course's analyzers, which catch the mistakes
+// the lessons warn about:
+//
+//	blankuse  _ = x statements that only silence "declared and not used" (day3/variables)
+//	naming    snake_case names, capitalised locals, mixed-case initialisms (day4/naming)
+//	nilzero   writes to never-made maps, dereferences of never-assigned pointers (day4/zerovalues)
+//
+// Run it on packages like go vet, or as its vet tool:
+//
+//	lessonvet ./Assignments/...
+//	go vet -vettool=$(which lessonvet) ./...
+//
+// -fix applies the suggested fixes.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"github.com/ALS240/GoTrainings/internal/analyzers/blankuse"
+	"github.com/ALS240/GoTrainings/internal/analyzers/naming"
+	"github.com/ALS240/GoTrainings/internal/analyzers/nilzero"
+)
+
+func main() {
+	multichecker.Main(
+		blankuse.Analyzer,
+		naming.Analyzer,
+		nilzero.Analyzer,
+	)
+}
@@ -0,0 +1,76 @@
+// Command scopetree prints the lexical scopes of a Go file or lesson as a
+// tree, from the universe down through the package, the files, every
+// function and every block, with each name declared in them. Names that
+// shadow a name from an enclosing scope are marked with "!" and shown
+// with both declaration sites.
+//
+// Usage:
+//
+//	scopetree [-html] [-o file] <lesson or path>
+//
+// The target is a lesson name understood by gotrain (e.g. day3/variables),
+// a package directory or a single .go file, such as a student's answer to
+// the Day 4 shadowing exercise. Type errors do not stop the tree from
+// being printed. -html writes a page with the source and the tree side by
+// side, for showing in class.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ALS240/GoTrainings/internal/lessons"
+	"github.com/ALS240/GoTrainings/internal/scopetree"
+)
+
+func main() {
+	asHTML := flag.Bool("html", false, "write an HTML page instead of text")
+	out := flag.String("o", "", "write to `file` instead of standard output")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: scopetree [-html] [-o file] <lesson or path>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir, target, err := lessons.Target(flag.Arg(0))
+	if err != nil {
+		fatal(err)
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(dir, target)
+	}
+	tree, err := scopetree.Load(target)
+	if err != nil {
+		fatal(err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if *asHTML {
+		err = tree.WriteHTML(w)
+	} else {
+		err = tree.WriteText(w)
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "scopetree:", err)
+	os.Exit(1)
+}
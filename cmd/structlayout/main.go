@@ -0,0 +1,118 @@
+// Command structlayout prints the memory layout of struct types declared in
+// Go source: every field's offset, size and alignment and the padding
+// between fields, for amd64, 386 and arm64, followed by a field order that
+// needs less padding when there is one.
+//
+// Usage:
+//
+//	structlayout [-arch amd64,386,arm64] [-json] <lesson or path> [Type...]
+//
+// The target is a lesson name understood by gotrain (e.g. day4/escape), a
+// package directory or a single .go file. Without type names every
+// package-level struct is reported.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ALS240/GoTrainings/internal/lessons"
+	"github.com/ALS240/GoTrainings/pkg/layout"
+)
+
+func main() {
+	arches := flag.String("arch", strings.Join(layout.Arches, ","), "comma-separated architectures")
+	asJSON := flag.Bool("json", false, "print the layouts and suggested orders as JSON")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: structlayout [-arch amd64,386,arm64] [-json] <lesson or path> [Type...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir, target, err := lessons.Target(flag.Arg(0))
+	if err != nil {
+		fatal(err)
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(dir, target)
+	}
+	decls, err := layout.Load(target)
+	if err != nil {
+		fatal(err)
+	}
+	if names := flag.Args()[1:]; len(names) > 0 {
+		decls, err = pick(decls, names)
+		if err != nil {
+			fatal(err)
+		}
+	}
+	if len(decls) == 0 {
+		fatal(fmt.Errorf("no struct types in %s", flag.Arg(0)))
+	}
+
+	type entry struct {
+		Layout    *layout.Struct `json:"layout"`
+		Suggested *layout.Struct `json:"suggested,omitempty"`
+	}
+	var entries []entry
+	for _, d := range decls {
+		for _, arch := range strings.Split(*arches, ",") {
+			s, err := layout.FromTypes(d.Name, d.Struct, strings.TrimSpace(arch))
+			if err != nil {
+				fatal(err)
+			}
+			e := entry{Layout: s}
+			if !s.Optimal() {
+				e.Suggested = s.Optimized()
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			fatal(err)
+		}
+		return
+	}
+	for i, e := range entries {
+		if i > 0 {
+			fmt.Println()
+		}
+		e.Layout.WriteText(os.Stdout)
+		e.Layout.WriteSuggestion(os.Stdout)
+	}
+}
+
+// pick keeps the declarations named in names, in that order.
+func pick(decls []layout.Decl, names []string) ([]layout.Decl, error) {
+	var out []layout.Decl
+	for _, name := range names {
+		found := false
+		for _, d := range decls {
+			if d.Name == name {
+				out = append(out, d)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no struct type %s", name)
+		}
+	}
+	return out, nil
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "structlayout:", err)
+	os.Exit(1)
+}
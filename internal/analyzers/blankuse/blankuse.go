@@ -0,0 +1,304 @@
+// Package blankuse defines an analyzer for `_ = x` statements that exist
+// only to silence the compiler's "declared and not used" error, as in
+//
+//	discard := "value"
+//	_ = discard
+//
+// Such a variable is dead: nothing reads it, and the blank assignment
+// hides that. The analyzer suggests removing the variable together with
+// the assignment. When x is read elsewhere, or is a parameter, which the
+// compiler never reports, the assignment is merely redundant and removing
+// it is suggested instead.
+//
+// Discarding values that a statement produces is left alone: _, err := f()
+// ignores one of several results, and _ = f() ignores a call's result.
+package blankuse
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const (
+	Lesson    = "day3/variables"
+	LessonURL = "https://github.com/ALS240/GoTrainings/blob/main/Codes/Day3/variables.go"
+)
+
+// Analyzer reports blank assignments that keep dead variables alive.
+var Analyzer = &analysis.Analyzer{
+	Name:     "blankuse",
+	Doc:      "report `_ = x` assignments that only silence \"declared and not used\"\n\nA local variable whose only use is `_ = x` is dead; remove both. Blank\ndiscards of results, such as `_, err := f()`, are not reported.",
+	URL:      LessonURL,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// variable is what the package does with one local variable.
+type variable struct {
+	decl    ast.Node          // the statement declaring it, or nil for a parameter
+	blanks  []*ast.AssignStmt // `_ = x` statements naming it
+	reads   []token.Pos       // every other use that reads it
+	assigns int               // assignments to it after the declaration
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	vars := map[*types.Var]*variable{}
+	get := func(v *types.Var) *variable {
+		if vars[v] == nil {
+			vars[v] = &variable{}
+		}
+		return vars[v]
+	}
+
+	// Record where locals are declared, and which are parameters.
+	insp.WithStack([]ast.Node{(*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		id := n.(*ast.Ident)
+		v, ok := pass.TypesInfo.Defs[id].(*types.Var)
+		if !ok || v.IsField() || v.Parent() == nil || v.Parent() == pass.Pkg.Scope() {
+			return true
+		}
+		info := get(v)
+		for i := len(stack) - 2; i >= 0; i-- {
+			switch s := stack[i].(type) {
+			case *ast.FieldList:
+				return true // a parameter or result: decl stays nil
+			case *ast.AssignStmt, *ast.DeclStmt, *ast.RangeStmt, *ast.TypeSwitchStmt:
+				info.decl = s
+				return true
+			}
+		}
+		return true
+	})
+
+	// Sort every use into blank assignments, plain assignments and reads.
+	insp.Preorder([]ast.Node{(*ast.AssignStmt)(nil), (*ast.IncDecStmt)(nil)}, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok == token.ASSIGN && allBlank(n.Lhs) {
+				for _, rhs := range n.Rhs {
+					if v := localVar(pass, rhs); v != nil && vars[v] != nil {
+						vars[v].blanks = append(vars[v].blanks, n)
+					}
+				}
+			}
+			if n.Tok != token.DEFINE {
+				for _, lhs := range n.Lhs {
+					if v := localVar(pass, lhs); v != nil && vars[v] != nil {
+						vars[v].assigns++
+					}
+				}
+			}
+		case *ast.IncDecStmt:
+			if v := localVar(pass, n.X); v != nil && vars[v] != nil {
+				vars[v].assigns++
+			}
+		}
+	})
+	skip := map[*ast.Ident]bool{}
+	for _, info := range vars {
+		for _, b := range info.blanks {
+			for _, rhs := range b.Rhs {
+				if id, ok := ast.Unparen(rhs).(*ast.Ident); ok {
+					skip[id] = true
+				}
+			}
+		}
+	}
+	insp.WithStack([]ast.Node{(*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		id := n.(*ast.Ident)
+		v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+		if !ok || vars[v] == nil || skip[id] || assignedTo(id, stack) {
+			return true
+		}
+		vars[v].reads = append(vars[v].reads, id.Pos())
+		return true
+	})
+
+	var found []analysis.Diagnostic
+	for v, info := range vars {
+		for _, b := range info.blanks {
+			found = append(found, diagnose(pass, v, info, b))
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Pos < found[j].Pos })
+	for _, d := range found {
+		pass.Report(d)
+	}
+	return nil, nil
+}
+
+func diagnose(pass *analysis.Pass, v *types.Var, info *variable, blank *ast.AssignStmt) analysis.Diagnostic {
+	d := analysis.Diagnostic{Pos: blank.Pos(), End: blank.End(), URL: LessonURL}
+	switch {
+	case info.decl == nil:
+		d.Message = fmt.Sprintf("_ = %s does nothing: parameters are never reported as unused (see %s)", v.Name(), Lesson)
+		d.SuggestedFixes = removeFix(pass, "Remove _ = "+v.Name(), blank)
+	case len(info.reads) > 0:
+		d.Message = fmt.Sprintf("_ = %s is redundant: %s is used at line %d (see %s)",
+			v.Name(), v.Name(), pass.Fset.Position(info.reads[0]).Line, Lesson)
+		d.SuggestedFixes = removeFix(pass, "Remove _ = "+v.Name(), blank)
+	default:
+		d.Message = fmt.Sprintf("%s is never used; _ = %s only silences the \"declared and not used\" error. Remove the variable and this assignment (see %s)",
+			v.Name(), v.Name(), Lesson)
+		if len(blank.Rhs) == 1 && len(info.blanks) == 1 && info.assigns == 0 && removable(pass, info.decl) {
+			d.SuggestedFixes = removeFix(pass, fmt.Sprintf("Remove %s and _ = %s", v.Name(), v.Name()), info.decl, blank)
+		}
+	}
+	return d
+}
+
+// removable reports whether deleting decl cannot change what the program
+// does: it declares a single variable from nothing or from an expression
+// without side effects.
+func removable(pass *analysis.Pass, decl ast.Node) bool {
+	var names []*ast.Ident
+	var values []ast.Expr
+	switch decl := decl.(type) {
+	case *ast.AssignStmt:
+		for _, lhs := range decl.Lhs {
+			names = append(names, lhs.(*ast.Ident))
+		}
+		values = decl.Rhs
+	case *ast.DeclStmt:
+		gen := decl.Decl.(*ast.GenDecl)
+		if len(gen.Specs) != 1 {
+			return false
+		}
+		spec, ok := gen.Specs[0].(*ast.ValueSpec)
+		if !ok {
+			return false
+		}
+		names, values = spec.Names, spec.Values
+	default:
+		return false
+	}
+	if len(names) != 1 {
+		return false
+	}
+	for _, e := range values {
+		if !pure(pass, e) {
+			return false
+		}
+	}
+	return true
+}
+
+// pure reports whether evaluating e has no effect besides its value:
+// constants, variables, function literals and composite literals of
+// those.
+func pure(pass *analysis.Pass, e ast.Expr) bool {
+	if tv, ok := pass.TypesInfo.Types[e]; ok && tv.Value != nil {
+		return true
+	}
+	switch e := ast.Unparen(e).(type) {
+	case *ast.Ident, *ast.FuncLit, *ast.BasicLit:
+		return true
+	case *ast.CompositeLit:
+		for _, elt := range e.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+			if !pure(pass, elt) {
+				return false
+			}
+		}
+		return true
+	case *ast.UnaryExpr:
+		return e.Op == token.AND && pure(pass, e.X)
+	}
+	return false
+}
+
+// removeFix deletes the statements, with the lines they are on when
+// nothing else is on them, and a blank line left hanging at the start of
+// a block or after another blank line.
+func removeFix(pass *analysis.Pass, msg string, stmts ...ast.Node) []analysis.SuggestedFix {
+	var edits []analysis.TextEdit
+	for _, n := range stmts {
+		pos, end := n.Pos(), n.End()
+		tf := pass.Fset.File(pos)
+		src, err := pass.ReadFile(tf.Name())
+		if err == nil {
+			start, stop := tf.Offset(pos), tf.Offset(end)
+			for start > 0 && (src[start-1] == ' ' || src[start-1] == '\t') {
+				start--
+			}
+			for stop < len(src) && (src[stop] == ' ' || src[stop] == '\t') {
+				stop++
+			}
+			if (start == 0 || src[start-1] == '\n') && stop < len(src) && src[stop] == '\n' {
+				pos, end = tf.Pos(start), tf.Pos(stop+1)
+			}
+		}
+		if k := len(edits) - 1; k >= 0 && edits[k].End == pos {
+			edits[k].End = end // adjacent lines: one edit
+		} else {
+			edits = append(edits, analysis.TextEdit{Pos: pos, End: end})
+		}
+	}
+	for i, e := range edits {
+		tf := pass.Fset.File(e.Pos)
+		src, err := pass.ReadFile(tf.Name())
+		if err != nil {
+			continue
+		}
+		start, stop := tf.Offset(e.Pos), tf.Offset(e.End)
+		if start == 0 || src[start-1] != '\n' {
+			continue // not whole lines
+		}
+		prev := bytes.TrimSpace(src[bytes.LastIndexByte(src[:start-1], '\n')+1 : start-1])
+		next := stop
+		for next < len(src) && (src[next] == ' ' || src[next] == '\t') {
+			next++
+		}
+		if (len(prev) == 0 || bytes.HasSuffix(prev, []byte("{"))) && next < len(src) && src[next] == '\n' {
+			edits[i].End = tf.Pos(next + 1)
+		}
+	}
+	return []analysis.SuggestedFix{{Message: msg, TextEdits: edits}}
+}
+
+func allBlank(exprs []ast.Expr) bool {
+	for _, e := range exprs {
+		if id, ok := e.(*ast.Ident); !ok || id.Name != "_" {
+			return false
+		}
+	}
+	return true
+}
+
+// localVar returns the local variable e names, if it names one.
+func localVar(pass *analysis.Pass, e ast.Expr) *types.Var {
+	id, ok := ast.Unparen(e).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	v, _ := pass.TypesInfo.Uses[id].(*types.Var)
+	return v
+}
+
+// assignedTo reports whether id, the last node on stack, is the target of
+// a plain assignment. Like the compiler, it counts x += 1 and x++ as uses.
+func assignedTo(id *ast.Ident, stack []ast.Node) bool {
+	parent := stack[len(stack)-2]
+	if _, ok := parent.(*ast.ParenExpr); ok && len(stack) > 2 {
+		parent = stack[len(stack)-3]
+	}
+	if p, ok := parent.(*ast.AssignStmt); ok && (p.Tok == token.ASSIGN || p.Tok == token.DEFINE) {
+		for _, lhs := range p.Lhs {
+			if ast.Unparen(lhs) == id {
+				return true
+			}
+		}
+	}
+	return false
+}
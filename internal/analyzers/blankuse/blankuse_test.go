@@ -0,0 +1,13 @@
+package blankuse_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/ALS240/GoTrainings/internal/analyzers/blankuse"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), blankuse.Analyzer, "a")
+}
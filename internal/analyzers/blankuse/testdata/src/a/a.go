@@ -0,0 +1,54 @@
+package a
+
+import "fmt"
+
+func pair() (int, error) { return 1, nil }
+
+func dead() {
+	discard := "value"
+	_ = discard // want `discard is never used; _ = discard only silences the "declared and not used" error. Remove the variable and this assignment \(see day3/variables\)`
+
+	var unused int
+	_ = unused // want `unused is never used; _ = unused only silences`
+	fmt.Println("done")
+}
+
+func deadWithEffects() {
+	n, _ := pair()
+	_ = n // want `n is never used; _ = n only silences`
+
+	// Assignments are not uses, but the fix would lose them.
+	v := 1
+	v = 2
+	_ = v // want `v is never used; _ = v only silences`
+}
+
+func redundant() {
+	x := 1
+	fmt.Println(x)
+	_ = x // want `_ = x is redundant: x is used at line 28 \(see day3/variables\)`
+}
+
+func counted() {
+	i := 0
+	i++
+	_ = i // want `_ = i is redundant: i is used at line 34`
+
+	j := 0
+	j += 1
+	_ = j // want `_ = j is redundant: j is used at line 38`
+}
+
+func parameter(p int) {
+	_ = p // want `_ = p does nothing: parameters are never reported as unused \(see day3/variables\)`
+	fmt.Println("parameter")
+}
+
+func discards() {
+	_, err := pair()
+	if err != nil {
+		return
+	}
+	_, _ = pair()
+	_ = fmt.Sprint("ignored")
+}
@@ -0,0 +1,349 @@
+// Package naming defines an analyzer for the naming rules of Day 4
+// (Codes/Day4/01_naming): names are camelCase, the case of the first
+// letter decides whether a name is exported, and _ is only the blank
+// identifier. It reports
+//
+//   - snake_case and SCREAMING_CASE names: user_name, MAX_SIZE;
+//   - capitalised local variables and constants, which cannot be exported:
+//     var MyPresence = 10 inside a function;
+//   - initialisms written in mixed case: userId, HttpClient, parseJson.
+//
+// Each diagnostic carries a suggested fix that renames the identifier and
+// every use of it, unless the rename could break code elsewhere: methods
+// (interface satisfaction), exported names outside package main, and
+// names that would collide with another one in scope.
+//
+// Code that needs a bad name on purpose, such as one matching an outside
+// format, silences the report with a directive on the line of the name
+// or the line above it:
+//
+//	//lessonvet:ignore naming matches the column name in the CSV export
+//	var user_id = row[0]
+//
+// The lessons keep their examples reported: MyPresence in day4/naming is
+// what the analyzer is for.
+package naming
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const (
+	Lesson    = "day4/naming"
+	LessonURL = "https://github.com/ALS240/GoTrainings/blob/main/Codes/Day4/01_naming/main.go"
+)
+
+// Analyzer reports names that break the Day 4 naming rules.
+var Analyzer = &analysis.Analyzer{
+	Name: "naming",
+	Doc:  "report snake_case names, capitalised locals and mixed-case initialisms\n\nGo names are camelCase; only package-level names can be exported, and\ninitialisms keep one case throughout: userID, HTTPClient, parseJSON.",
+	URL:  LessonURL,
+	Run:  run,
+}
+
+// initialisms are written all upper or all lower case, as in the standard
+// library: ServeHTTP, userID, jsonData. The list is golint's.
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true,
+	"EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "LHS": true, "QPS": true, "RAM": true, "RHS": true,
+	"RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true, "XMPP": true,
+	"XSRF": true, "XSS": true,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	// fields maps each struct field to its struct, to find name clashes.
+	fields := map[*types.Var]*types.Struct{}
+	for _, tv := range pass.TypesInfo.Types {
+		if st, ok := tv.Type.(*types.Struct); ok {
+			for i := range st.NumFields() {
+				fields[st.Field(i)] = st
+			}
+		}
+	}
+
+	for _, f := range pass.Files {
+		if ast.IsGenerated(f) {
+			continue
+		}
+		test := strings.HasSuffix(pass.Fset.File(f.Pos()).Name(), "_test.go")
+		ignored := ignoredLines(pass.Fset, f)
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				if test && n.Recv == nil && testFunc(n.Name.Name) {
+					ast.Inspect(n.Type, checkIdents(pass, fields, ignored))
+					if n.Body != nil {
+						ast.Inspect(n.Body, checkIdents(pass, fields, ignored))
+					}
+					return false
+				}
+			case *ast.Ident:
+				check(pass, n, fields, ignored)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+func checkIdents(pass *analysis.Pass, fields map[*types.Var]*types.Struct, ignored map[int]bool) func(ast.Node) bool {
+	return func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			check(pass, id, fields, ignored)
+		}
+		return true
+	}
+}
+
+// ignoredLines returns the lines of f whose names are not reported: the
+// line of each //lessonvet:ignore naming directive and the line after it.
+func ignoredLines(fset *token.FileSet, f *ast.File) map[int]bool {
+	lines := map[int]bool{}
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			args := strings.Fields(strings.TrimPrefix(c.Text, "//lessonvet:ignore"))
+			if !strings.HasPrefix(c.Text, "//lessonvet:ignore ") || len(args) == 0 ||
+				!slices.Contains(strings.Split(args[0], ","), "naming") {
+				continue
+			}
+			line := fset.Position(c.Slash).Line
+			lines[line], lines[line+1] = true, true
+		}
+	}
+	return lines
+}
+
+// testFunc reports whether name is a test, benchmark, example or fuzz
+// function, whose underscores are part of the go test naming scheme.
+func testFunc(name string) bool {
+	for _, prefix := range []string{"Test", "Benchmark", "Example", "Fuzz"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// check reports id if it declares a badly named object.
+func check(pass *analysis.Pass, id *ast.Ident, fields map[*types.Var]*types.Struct, ignored map[int]bool) {
+	if id.Name == "_" || ignored[pass.Fset.Position(id.Pos()).Line] {
+		return
+	}
+	obj := pass.TypesInfo.Defs[id]
+	if obj == nil {
+		return // a use, or a name that declares nothing (package clause, type switch)
+	}
+	if v, ok := obj.(*types.Var); ok && v.Embedded() {
+		return // the type's own name is checked where it is declared
+	}
+	if _, ok := obj.(*types.Func); ok && obj.Name() == "main" {
+		return
+	}
+
+	want, reasons := Suggest(id.Name, local(pass, obj))
+	if want == id.Name {
+		return
+	}
+	pass.Report(analysis.Diagnostic{
+		Pos:            id.Pos(),
+		End:            id.End(),
+		Message:        fmt.Sprintf("%s %s; name it %s (see %s)", id.Name, strings.Join(reasons, " and "), want, Lesson),
+		URL:            LessonURL,
+		SuggestedFixes: rename(pass, obj, want, fields),
+	})
+}
+
+// local reports whether obj is a variable or constant declared inside a
+// function, which cannot be exported whatever its case.
+func local(pass *analysis.Pass, obj types.Object) bool {
+	switch obj := obj.(type) {
+	case *types.Var:
+		if obj.IsField() {
+			return false
+		}
+	case *types.Const:
+	default:
+		return false
+	}
+	return obj.Parent() != nil && obj.Parent() != pass.Pkg.Scope()
+}
+
+// Suggest returns the name name should have and why, or name itself when
+// it follows the rules. local is set for variables and constants declared
+// in a function, which should not start with an upper-case letter.
+func Suggest(name string, local bool) (string, []string) {
+	ws := words(name)
+	if len(ws) == 0 {
+		return name, nil
+	}
+	first, _ := firstRune(name)
+	exported := unicode.IsUpper(first) && !local
+
+	var reasons []string
+	if strings.Contains(strings.Trim(name, "_"), "_") || strings.HasPrefix(name, "_") {
+		reasons = append(reasons, "uses underscores (Go names are camelCase)")
+		return camel(ws, exported, true), reasons
+	}
+	if unicode.IsUpper(first) && local {
+		reasons = append(reasons, "is capitalised but local to a function, so it cannot be exported")
+	}
+	for _, w := range ws {
+		if fixed := initialism(w); fixed != "" && fixed != w && w != strings.ToLower(w) {
+			reasons = append(reasons, fmt.Sprintf("writes the initialism %s as %s", fixed, w))
+		}
+	}
+	if len(reasons) == 0 {
+		return name, nil
+	}
+	return camel(ws, exported, false), reasons
+}
+
+// camel joins words into a camelCase name. With normalise, words that
+// are not initialisms are title-cased (MAX becomes Max); otherwise they
+// are kept as written.
+func camel(ws []string, exported, normalise bool) string {
+	var b strings.Builder
+	for i, w := range ws {
+		fixed := initialism(w)
+		switch {
+		case i == 0 && !exported:
+			if fixed != "" || normalise {
+				w = strings.ToLower(w)
+			} else {
+				w = lowerFirst(w)
+			}
+		case fixed != "":
+			w = fixed
+		case normalise:
+			w = title(strings.ToLower(w))
+		default:
+			w = title(w)
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+// initialism returns w spelt as an initialism (Id -> ID, Ids -> IDs), or
+// "" if w is not one.
+func initialism(w string) string {
+	if up := strings.ToUpper(w); initialisms[up] {
+		return up
+	}
+	if stem, ok := strings.CutSuffix(w, "s"); ok && len(stem) > 1 && initialisms[strings.ToUpper(stem)] {
+		return strings.ToUpper(stem) + "s"
+	}
+	return ""
+}
+
+// words splits a name at underscores and camelCase boundaries:
+// HTTPServer_port2 -> HTTP, Server, port2.
+func words(name string) []string {
+	var ws []string
+	for _, part := range strings.Split(name, "_") {
+		r := []rune(part)
+		start := 0
+		for i := 1; i < len(r); i++ {
+			upper := unicode.IsUpper(r[i])
+			prevLowerOrDigit := unicode.IsLower(r[i-1]) || unicode.IsDigit(r[i-1])
+			acronymEnd := unicode.IsUpper(r[i-1]) && i+1 < len(r) && unicode.IsLower(r[i+1])
+			if upper && (prevLowerOrDigit || acronymEnd) {
+				ws = append(ws, string(r[start:i]))
+				start = i
+			}
+		}
+		if start < len(r) {
+			ws = append(ws, string(r[start:]))
+		}
+	}
+	return ws
+}
+
+// rename returns a fix that renames obj to name throughout the package,
+// or nil when that could break code the analyzer cannot see or change.
+func rename(pass *analysis.Pass, obj types.Object, name string, fields map[*types.Var]*types.Struct) []analysis.SuggestedFix {
+	if fn, ok := obj.(*types.Func); ok && fn.Signature().Recv() != nil {
+		return nil // the method may implement an interface
+	}
+	if obj.Exported() && !local(pass, obj) && pass.Pkg.Name() != "main" {
+		return nil // importers use the old name
+	}
+	v, isVar := obj.(*types.Var)
+	field := isVar && v.IsField()
+	if field {
+		st := fields[v]
+		if st == nil {
+			return nil
+		}
+		for i := range st.NumFields() {
+			if st.Field(i).Name() == name {
+				return nil
+			}
+		}
+	}
+
+	var edits []analysis.TextEdit
+	clash := false
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			id, ok := n.(*ast.Ident)
+			if !ok || pass.TypesInfo.ObjectOf(id) != obj {
+				return true
+			}
+			// The new name must not be taken where the old one is
+			// declared or used, or the rename would change what a
+			// use refers to. Fields are reached through selectors
+			// and were checked against their struct above.
+			if !field {
+				if s := pass.Pkg.Scope().Innermost(id.Pos()); s != nil {
+					if _, other := s.LookupParent(name, token.NoPos); other != nil && other != obj {
+						clash = true
+					}
+				}
+			}
+			edits = append(edits, analysis.TextEdit{Pos: id.Pos(), End: id.End(), NewText: []byte(name)})
+			return true
+		})
+	}
+	if clash {
+		return nil
+	}
+	return []analysis.SuggestedFix{{
+		Message:   fmt.Sprintf("Rename %s to %s", obj.Name(), name),
+		TextEdits: edits,
+	}}
+}
+
+func firstRune(s string) (rune, bool) {
+	for _, r := range strings.TrimLeft(s, "_") {
+		return r, true
+	}
+	return 0, false
+}
+
+func title(w string) string {
+	r, ok := firstRune(w)
+	if !ok {
+		return w
+	}
+	return string(unicode.ToUpper(r)) + w[len(string(r)):]
+}
+
+func lowerFirst(w string) string {
+	r, ok := firstRune(w)
+	if !ok {
+		return w
+	}
+	return string(unicode.ToLower(r)) + w[len(string(r)):]
+}
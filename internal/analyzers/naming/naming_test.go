@@ -0,0 +1,40 @@
+package naming_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/ALS240/GoTrainings/internal/analyzers/naming"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), naming.Analyzer, "a")
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name  string
+		local bool
+		want  string
+	}{
+		{"userName", false, "userName"},
+		{"user_name", false, "userName"},
+		{"MAX_SIZE", false, "MaxSize"},
+		{"_private", false, "private"},
+		{"MyPresence", true, "myPresence"},
+		{"MyPresence", false, "MyPresence"},
+		{"userId", false, "userID"},
+		{"HttpClient", false, "HTTPClient"},
+		{"parseJson", false, "parseJSON"},
+		{"userIds", false, "userIDs"},
+		{"ID", true, "id"},
+		{"jsonData", false, "jsonData"},
+		{"ServeHTTP", false, "ServeHTTP"},
+	}
+	for _, tt := range tests {
+		if got, _ := naming.Suggest(tt.name, tt.local); got != tt.want {
+			t.Errorf("Suggest(%q, %v) = %q, want %q", tt.name, tt.local, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package a
+
+import "fmt"
+
+const MAX_SIZE = 10 // want `MAX_SIZE uses underscores \(Go names are camelCase\); name it MaxSize \(see day4/naming\)`
+
+const max_retries = 3 // want `max_retries uses underscores \(Go names are camelCase\); name it maxRetries`
+
+type HttpClient struct { // want `HttpClient writes the initialism HTTP as Http; name it HTTPClient`
+	userId int // want `userId writes the initialism ID as Id; name it userID`
+	userID int
+}
+
+// Methods may implement an interface, so they are reported but not renamed.
+func (c HttpClient) GetUrl() string { return "" } // want `GetUrl writes the initialism URL as Url; name it GetURL`
+
+func locals() {
+	user_name := "gopher" // want `user_name uses underscores \(Go names are camelCase\); name it userName`
+	fmt.Println(user_name, max_retries)
+
+	var MyPresence = 10 // want `MyPresence is capitalised but local to a function, so it cannot be exported; name it myPresence`
+	fmt.Println(MyPresence)
+
+	// A rename to a name already in scope would change what uses refer to.
+	count := 1
+	var Count = 2 // want `Count is capitalised but local to a function, so it cannot be exported; name it count`
+	fmt.Println(count, Count)
+
+	//lessonvet:ignore naming shown on purpose
+	var Shown = 1
+	var Inline = 2 //lessonvet:ignore naming,blankuse shown on purpose
+	fmt.Println(Shown, Inline)
+}
+
+func parseJson(s string) (Result string) { // want `parseJson writes the initialism JSON as Json; name it parseJSON` `Result is capitalised but local to a function, so it cannot be exported; name it result`
+	Result = s
+	return Result
+}
+
+func use() {
+	fmt.Println(parseJson(""), MAX_SIZE)
+}
@@ -0,0 +1,259 @@
+// Package nilzero defines an analyzer for the zero-value pitfalls of
+// Day 4: writing to a map declared with var and never made, and
+// dereferencing a pointer declared with var and never assigned. Both
+// always panic, so the analyzer only reports variables whose zero value
+// is the only value they can ever hold: declared without an initialiser,
+// never assigned and never passed by address, explicitly or as the
+// receiver of a pointer method.
+package nilzero
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Lesson is the zero-value lesson every diagnostic links to.
+const (
+	Lesson    = "day4/zerovalues"
+	LessonURL = "https://github.com/ALS240/GoTrainings/blob/main/Codes/Day4/05_ZeroValues/zerovalues.go"
+)
+
+// Analyzer reports nil map writes and nil pointer dereferences on
+// variables that are never anything but their zero value.
+var Analyzer = &analysis.Analyzer{
+	Name:     "nilzero",
+	Doc:      "report writes to never-made maps and dereferences of never-assigned pointers\n\nA variable declared as `var m map[K]V` or `var p *T` and never assigned\nholds nil for its whole life: m[k] = v and *p always panic.",
+	URL:      LessonURL,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// candidate is a map or pointer variable declared without a value.
+type candidate struct {
+	spec     *ast.ValueSpec
+	assigned bool // assigned, or its address taken, somewhere in the package
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	vars := map[*types.Var]*candidate{}
+	insp.Preorder([]ast.Node{(*ast.ValueSpec)(nil)}, func(n ast.Node) {
+		spec := n.(*ast.ValueSpec)
+		if len(spec.Values) > 0 {
+			return
+		}
+		for _, name := range spec.Names {
+			v, ok := pass.TypesInfo.Defs[name].(*types.Var)
+			if !ok || !nilPointerOrMap(v.Type()) {
+				continue
+			}
+			// Exported package-level variables may be set by importers.
+			if v.Parent() == pass.Pkg.Scope() && v.Exported() {
+				continue
+			}
+			vars[v] = &candidate{spec: spec}
+		}
+	})
+	if len(vars) == 0 {
+		return nil, nil
+	}
+
+	markAssigned := func(e ast.Expr) {
+		if id, ok := ast.Unparen(e).(*ast.Ident); ok {
+			if c := vars[objOf(pass, id)]; c != nil {
+				c.assigned = true
+			}
+		}
+	}
+	insp.Preorder([]ast.Node{(*ast.AssignStmt)(nil), (*ast.UnaryExpr)(nil), (*ast.RangeStmt)(nil), (*ast.SelectorExpr)(nil)}, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				markAssigned(lhs)
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				markAssigned(n.X)
+			}
+		case *ast.RangeStmt:
+			if n.Tok == token.ASSIGN {
+				markAssigned(n.Key)
+				markAssigned(n.Value)
+			}
+		case *ast.SelectorExpr:
+			// m.Init() with func (m *M) Init() takes &m implicitly, and
+			// the method may well assign *m.
+			if sel := pass.TypesInfo.Selections[n]; sel != nil && implicitAddr(sel) {
+				markAssigned(n.X)
+			}
+		}
+	})
+
+	// zeroOnly returns the candidate e names, if it is never anything but nil.
+	zeroOnly := func(e ast.Expr) (*types.Var, *candidate) {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		if !ok {
+			return nil, nil
+		}
+		v := objOf(pass, id)
+		if c := vars[v]; c != nil && !c.assigned {
+			return v, c
+		}
+		return nil, nil
+	}
+
+	insp.Preorder([]ast.Node{
+		(*ast.AssignStmt)(nil),
+		(*ast.IncDecStmt)(nil),
+		(*ast.StarExpr)(nil),
+		(*ast.SelectorExpr)(nil),
+		(*ast.IndexExpr)(nil),
+	}, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				mapWrite(pass, lhs, zeroOnly)
+			}
+		case *ast.IncDecStmt:
+			mapWrite(pass, n.X, zeroOnly)
+		case *ast.StarExpr:
+			// *T in a type position is not a dereference.
+			if tv, ok := pass.TypesInfo.Types[n]; ok && !tv.IsType() {
+				deref(pass, n, n.X, "*"+types.ExprString(n.X), zeroOnly)
+			}
+		case *ast.SelectorExpr:
+			// p.Field and p.ValueMethod() go through *p; p.PointerMethod()
+			// does not, and may well handle a nil receiver.
+			if sel := pass.TypesInfo.Selections[n]; sel != nil && throughPointer(sel) {
+				deref(pass, n, n.X, types.ExprString(n), zeroOnly)
+			}
+		case *ast.IndexExpr:
+			// p[i] on a pointer to an array.
+			if t, ok := pass.TypesInfo.TypeOf(n.X).Underlying().(*types.Pointer); ok {
+				if _, ok := t.Elem().Underlying().(*types.Array); ok {
+					deref(pass, n, n.X, types.ExprString(n), zeroOnly)
+				}
+			}
+		}
+	})
+	return nil, nil
+}
+
+// mapWrite reports lhs if it is an entry of a map that is only ever nil.
+func mapWrite(pass *analysis.Pass, lhs ast.Expr, zeroOnly func(ast.Expr) (*types.Var, *candidate)) {
+	idx, ok := ast.Unparen(lhs).(*ast.IndexExpr)
+	if !ok {
+		return
+	}
+	v, c := zeroOnly(idx.X)
+	if v == nil {
+		return
+	}
+	if _, ok := v.Type().Underlying().(*types.Map); !ok {
+		return
+	}
+	typ := typeString(pass, v.Type())
+	pass.Report(analysis.Diagnostic{
+		Pos: idx.Pos(),
+		End: idx.End(),
+		Message: fmt.Sprintf("assignment to entry in nil map: %s is declared with var and never made, so %s panics; initialise it with make(%s) (see %s, Common Initialization Patterns)",
+			v.Name(), types.ExprString(idx), typ, Lesson),
+		URL:            LessonURL,
+		SuggestedFixes: makeFix(pass, c.spec, v, "make("+typ+")"),
+	})
+}
+
+// deref reports n, which dereferences x, if x is a pointer that is only
+// ever nil.
+func deref(pass *analysis.Pass, n ast.Node, x ast.Expr, expr string, zeroOnly func(ast.Expr) (*types.Var, *candidate)) {
+	v, c := zeroOnly(x)
+	if v == nil {
+		return
+	}
+	ptr, ok := v.Type().Underlying().(*types.Pointer)
+	if !ok {
+		return
+	}
+	elem := typeString(pass, ptr.Elem())
+	pass.Report(analysis.Diagnostic{
+		Pos: n.Pos(),
+		End: n.End(),
+		Message: fmt.Sprintf("nil pointer dereference: %s is declared with var and never assigned, so %s panics; initialise it with new(%s) or the address of a value (see %s, Reference Types)",
+			v.Name(), expr, elem, Lesson),
+		URL:            LessonURL,
+		SuggestedFixes: makeFix(pass, c.spec, v, "new("+elem+")"),
+	})
+}
+
+// makeFix rewrites `var m T` as `var m = init` when the declaration names
+// a single variable.
+func makeFix(pass *analysis.Pass, spec *ast.ValueSpec, v *types.Var, init string) []analysis.SuggestedFix {
+	if len(spec.Names) != 1 || spec.Type == nil {
+		return nil
+	}
+	return []analysis.SuggestedFix{{
+		Message: fmt.Sprintf("Initialise %s with %s", v.Name(), init),
+		TextEdits: []analysis.TextEdit{{
+			Pos:     spec.Type.Pos(),
+			End:     spec.Type.End(),
+			NewText: []byte("= " + init),
+		}},
+	}}
+}
+
+// throughPointer reports whether x.f in sel reads *x. Selection.Indirect
+// is not reliable for methods, so a method's receiver is checked instead.
+func throughPointer(sel *types.Selection) bool {
+	switch sel.Kind() {
+	case types.FieldVal:
+		return sel.Indirect()
+	case types.MethodVal:
+		recv := sel.Obj().(*types.Func).Signature().Recv()
+		_, ptrRecv := recv.Type().(*types.Pointer)
+		return !ptrRecv || len(sel.Index()) > 1
+	}
+	return false
+}
+
+// implicitAddr reports whether x.m in sel calls a pointer method directly
+// on an addressable non-pointer x, passing &x.
+func implicitAddr(sel *types.Selection) bool {
+	if sel.Kind() != types.MethodVal || len(sel.Index()) != 1 {
+		return false
+	}
+	recv := sel.Obj().(*types.Func).Signature().Recv()
+	_, ptrRecv := recv.Type().(*types.Pointer)
+	_, ptrX := sel.Recv().(*types.Pointer)
+	return ptrRecv && !ptrX
+}
+
+func objOf(pass *analysis.Pass, id *ast.Ident) *types.Var {
+	v, _ := pass.TypesInfo.ObjectOf(id).(*types.Var)
+	return v
+}
+
+func nilPointerOrMap(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Map, *types.Pointer:
+		return true
+	}
+	return false
+}
+
+// typeString prints t as it would be written in the package being
+// analysed, qualifying only imported names.
+func typeString(pass *analysis.Pass, t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == pass.Pkg {
+			return ""
+		}
+		return p.Name()
+	})
+}
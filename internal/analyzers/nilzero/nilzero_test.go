@@ -0,0 +1,13 @@
+package nilzero_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/ALS240/GoTrainings/internal/analyzers/nilzero"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), nilzero.Analyzer, "a")
+}
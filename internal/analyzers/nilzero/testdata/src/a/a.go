@@ -0,0 +1,71 @@
+package a
+
+type point struct{ X, Y int }
+
+func (p point) Norm() int { return p.X*p.X + p.Y*p.Y }
+
+func (p *point) Reset() {
+	if p != nil {
+		*p = point{}
+	}
+}
+
+type M map[string]int
+
+func (m *M) Init() { *m = make(M) }
+
+var counts map[string]int
+
+func nilMap() {
+	counts["a"] = 1 // want `assignment to entry in nil map: counts is declared with var and never made`
+	counts["b"]++   // want `assignment to entry in nil map: counts is declared with var and never made`
+	_ = counts["c"] // reading a nil map is fine
+}
+
+func nilPointer() {
+	var p *point
+	_ = *p       // want `nil pointer dereference: p is declared with var and never assigned, so \*p panics`
+	_ = p.X      // want `nil pointer dereference: p is declared with var and never assigned, so p.X panics`
+	_ = p.Norm() // want `nil pointer dereference: p is declared with var and never assigned, so p.Norm panics`
+	p.Reset()    // a pointer method may handle nil
+}
+
+func made() {
+	var m map[string]int
+	m = make(map[string]int)
+	m["a"] = 1
+
+	var p *point
+	p = &point{}
+	_ = p.X
+}
+
+func byAddress() {
+	var m map[string]int
+	fill(&m)
+	m["a"] = 1
+}
+
+func fill(m *map[string]int) { *m = map[string]int{} }
+
+// A pointer-receiver method on an addressable map variable takes its
+// address and may make it.
+func byMethod() {
+	var m M
+	m.Init()
+	m["a"] = 1
+}
+
+func byRange(ms []map[string]int) {
+	var m map[string]int
+	for _, m = range ms {
+	}
+	m["a"] = 1
+}
+
+// Exported package-level variables may be set by importers.
+var Registry map[string]int
+
+func exported() {
+	Registry["a"] = 1
+}
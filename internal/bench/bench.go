@@ -0,0 +1,151 @@
+// Package bench runs Go benchmarks with -benchmem and summarises their
+// output as a comparison table, one group per top-level benchmark.
+package bench
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Result is one benchmark line.
+type Result struct {
+	Group       string  `json:"group"` // top-level benchmark, without "Benchmark"
+	Name        string  `json:"name"`  // sub-benchmark, or "" for a flat benchmark
+	Procs       int     `json:"procs"` // GOMAXPROCS suffix, 0 when absent
+	N           int     `json:"n"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	BytesPerOp  float64 `json:"bytes_per_op"`
+	AllocsPerOp float64 `json:"allocs_per_op"`
+}
+
+// Label is the name shown for r in a table.
+func (r Result) Label() string {
+	if r.Name == "" {
+		return r.Group
+	}
+	return r.Name
+}
+
+var (
+	lineRE  = regexp.MustCompile(`^Benchmark(\S+?)(?:-(\d+))?\s+(\d+)\s+(.*)$`)
+	valueRE = regexp.MustCompile(`([\d.]+) (\S+)`)
+)
+
+// Parse reads `go test -bench` output and returns the benchmark lines in
+// order. Other lines (goos, PASS, log output) are ignored.
+func Parse(r io.Reader) ([]Result, error) {
+	var results []Result
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		m := lineRE.FindStringSubmatch(strings.TrimSpace(sc.Text()))
+		if m == nil {
+			continue
+		}
+		res := Result{}
+		res.Group, res.Name, _ = strings.Cut(m[1], "/")
+		res.Procs, _ = strconv.Atoi(m[2])
+		res.N, _ = strconv.Atoi(m[3])
+		for _, v := range valueRE.FindAllStringSubmatch(m[4], -1) {
+			f, err := strconv.ParseFloat(v[1], 64)
+			if err != nil {
+				continue
+			}
+			switch v[2] {
+			case "ns/op":
+				res.NsPerOp = f
+			case "B/op":
+				res.BytesPerOp = f
+			case "allocs/op":
+				res.AllocsPerOp = f
+			}
+		}
+		results = append(results, res)
+	}
+	return results, sc.Err()
+}
+
+// Run runs the benchmarks of pkg matching pattern from dir and parses the
+// result. benchtime is passed to -benchtime unless empty.
+func Run(ctx context.Context, dir, pkg, pattern, benchtime string) ([]Result, error) {
+	args := []string{"test", "-run", "^$", "-bench", pattern, "-benchmem"}
+	if benchtime != "" {
+		args = append(args, "-benchtime", benchtime)
+	}
+	cmd := exec.CommandContext(ctx, "go", append(args, pkg)...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("go test -bench %s: %v\n%s%s", pkg, err, stdout.Bytes(), stderr.Bytes())
+	}
+	return Parse(&stdout)
+}
+
+// WriteTable writes results grouped by top-level benchmark. Within a
+// group every row is compared with the group's first row, so pairs such as
+// addNumbers/createCounter read as "the heap version is N times slower".
+func WriteTable(w io.Writer, results []Result) error {
+	rows := [][]string{{"benchmark", "ns/op", "B/op", "allocs/op", "vs first"}}
+	var base *Result
+	for i := range results {
+		r := &results[i]
+		if i == 0 || r.Group != results[i-1].Group {
+			if r.Name != "" {
+				rows = append(rows, []string{r.Group, "", "", "", ""})
+			}
+			base = r
+		}
+		label := r.Label()
+		if r.Name != "" {
+			label = "  " + label
+		}
+		rows = append(rows, []string{label, number(r.NsPerOp), number(r.BytesPerOp), number(r.AllocsPerOp), ratio(r, base)})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	bw := bufio.NewWriter(w)
+	for _, row := range rows {
+		line := fmt.Sprintf("%-*s", widths[0], row[0])
+		for i, cell := range row[1:] {
+			line += fmt.Sprintf("  %*s", widths[i+1], cell)
+		}
+		fmt.Fprintln(bw, strings.TrimRight(line, " "))
+	}
+	return bw.Flush()
+}
+
+// ratio compares r with the first result of its group.
+func ratio(r, base *Result) string {
+	switch {
+	case r == base || base.Name == "":
+		return ""
+	case base.NsPerOp == 0:
+		return "-"
+	case r.NsPerOp < base.NsPerOp:
+		return fmt.Sprintf("%.2fx", r.NsPerOp/base.NsPerOp)
+	}
+	return fmt.Sprintf("%.1fx", r.NsPerOp/base.NsPerOp)
+}
+
+func number(f float64) string {
+	switch {
+	case f == float64(int64(f)) || f >= 100:
+		return strconv.FormatFloat(f, 'f', 0, 64)
+	case f >= 10:
+		return strconv.FormatFloat(f, 'f', 1, 64)
+	default:
+		return strconv.FormatFloat(f, 'f', 2, 64)
+	}
+}
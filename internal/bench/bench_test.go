@@ -0,0 +1,79 @@
+package bench
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func parseFile(t *testing.T, name string) []Result {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	results, err := Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return results
+}
+
+// testdata/escape.txt is go test -bench output for the escape lesson,
+// testdata/pool.txt for pkg/pool with -cpu 1,4.
+func TestParse(t *testing.T) {
+	results := parseFile(t, "testdata/escape.txt")
+	if len(results) != 18 {
+		t.Fatalf("%d results, want 18", len(results))
+	}
+	tests := []struct {
+		i    int
+		want Result
+	}{
+		{0, Result{Group: "StackVsHeap", Name: "addNumbers", N: 100, NsPerOp: 6.2}},
+		{1, Result{Group: "StackVsHeap", Name: "createCounter", N: 100, NsPerOp: 34.3, BytesPerOp: 8, AllocsPerOp: 1}},
+		{7, Result{Group: "CreatePointers", N: 100, NsPerOp: 202.8, BytesPerOp: 79, AllocsPerOp: 5}},
+		{12, Result{Group: "CreateSlice", Name: "size=5000", N: 100, NsPerOp: 32248, BytesPerOp: 40960, AllocsPerOp: 1}},
+		{17, Result{Group: "BufferReuse", Name: "getBuffer-returnBuffer", N: 100, NsPerOp: 112.4}},
+	}
+	for _, tt := range tests {
+		if got := results[tt.i]; got != tt.want {
+			t.Errorf("result %d = %+v, want %+v", tt.i, got, tt.want)
+		}
+	}
+
+	results = parseFile(t, "testdata/pool.txt")
+	var procs []string
+	for _, r := range results {
+		procs = append(procs, fmt.Sprintf("%s:%d", r.Name, r.Procs))
+	}
+	want := "make:0 make:4 Pool:0 Pool:4 sync.Pool:0 sync.Pool:4"
+	if got := strings.Join(procs, " "); got != want {
+		t.Errorf("names and procs = %s, want %s", got, want)
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	var b strings.Builder
+	if err := WriteTable(&b, parseFile(t, "testdata/escape.txt")[:8]); err != nil {
+		t.Fatal(err)
+	}
+	want := `benchmark             ns/op  B/op  allocs/op  vs first
+StackVsHeap
+  addNumbers           6.20     0          0
+  createCounter        34.3     8          1      5.5x
+ValueVsPointer
+  getUserName          5.74     0          0
+  getUserNamePtr       33.2    16          1      5.8x
+Stack
+  calculateTotal       4.84     0          0
+  movePoint            3.53     0          0     0.73x
+  processCoordinates   2.90     0          0     0.60x
+CreatePointers          203    79          5
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteTable:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,165 @@
+// Package cheatsheet builds the Day 4 data-type cheat sheet from the type
+// system itself: sizes from unsafe.Sizeof, limits from the math constants
+// and zero values from reflect.Zero, for every predeclared type. Nothing is
+// typed in by hand, so int, uint, uintptr, string and error show the sizes
+// of the architecture the sheet is generated on.
+package cheatsheet
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// Row describes one predeclared type.
+type Row struct {
+	Type     string  `json:"type"`
+	Alias    string  `json:"alias,omitempty"` // "uint8" for byte, "int32" for rune
+	Kind     string  `json:"kind"`
+	Size     uintptr `json:"size"`
+	Zero     string  `json:"zero"`
+	Min      string  `json:"min,omitempty"`
+	Max      string  `json:"max,omitempty"`
+	MinHuman string  `json:"min_human,omitempty"`
+	MaxHuman string  `json:"max_human,omitempty"`
+	Notes    string  `json:"notes,omitempty"`
+}
+
+// Rows returns a row for every predeclared type, grouped by kind.
+func Rows() []Row {
+	return []Row{
+		signed[int8]("int8", math.MinInt8, math.MaxInt8),
+		signed[int16]("int16", math.MinInt16, math.MaxInt16),
+		signed[int32]("int32", math.MinInt32, math.MaxInt32),
+		signed[int64]("int64", math.MinInt64, math.MaxInt64),
+		note(signed[int]("int", math.MinInt, math.MaxInt), "same size as a pointer"),
+		alias(signed[rune]("rune", math.MinInt32, math.MaxInt32), "int32", "a Unicode code point"),
+
+		unsigned[uint8]("uint8", math.MaxUint8),
+		unsigned[uint16]("uint16", math.MaxUint16),
+		unsigned[uint32]("uint32", math.MaxUint32),
+		unsigned[uint64]("uint64", math.MaxUint64),
+		note(unsigned[uint]("uint", math.MaxUint), "same size as int"),
+		note(unsigned[uintptr]("uintptr", uint64(^uintptr(0))), "holds any pointer value"),
+		alias(unsigned[byte]("byte", math.MaxUint8), "uint8", "raw data, one UTF-8 code unit"),
+
+		floating[float32]("float32", math.MaxFloat32, math.SmallestNonzeroFloat32, 32),
+		floating[float64]("float64", math.MaxFloat64, math.SmallestNonzeroFloat64, 64),
+		note(floating[complex64]("complex64", math.MaxFloat32, math.SmallestNonzeroFloat32, 32), "two float32 parts; limits apply to each"),
+		note(floating[complex128]("complex128", math.MaxFloat64, math.SmallestNonzeroFloat64, 64), "two float64 parts; limits apply to each"),
+
+		base[bool]("bool", "boolean", "false", "true", "one byte, not one bit"),
+		base[string]("string", "string", "", "", "pointer + length; the bytes live elsewhere"),
+		base[error]("error", "interface", "", "", "type + value pointer; nil until a value is assigned"),
+	}
+}
+
+// base fills in what every row has: the size and zero value of T.
+func base[T any](name, kind, min, max, notes string) Row {
+	var zero T
+	return Row{
+		Type:  name,
+		Kind:  kind,
+		Size:  unsafe.Sizeof(zero),
+		Zero:  zeroString(reflect.Zero(reflect.TypeFor[T]())),
+		Min:   min,
+		Max:   max,
+		Notes: notes,
+	}
+}
+
+func signed[T ~int | ~int8 | ~int16 | ~int32 | ~int64](name string, min, max int64) Row {
+	r := base[T](name, "signed integer", strconv.FormatInt(min, 10), strconv.FormatInt(max, 10), "")
+	r.MinHuman, r.MaxHuman = humanInt(float64(min)), humanInt(float64(max))
+	return r
+}
+
+func unsigned[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr](name string, max uint64) Row {
+	r := base[T](name, "unsigned integer", "0", strconv.FormatUint(max, 10), "")
+	r.MinHuman, r.MaxHuman = "0", humanInt(float64(max))
+	return r
+}
+
+func floating[T ~float32 | ~float64 | ~complex64 | ~complex128](name string, max, smallest float64, bits int) Row {
+	kind := "floating point"
+	if strings.HasPrefix(name, "complex") {
+		kind = "complex"
+	}
+	exact := strconv.FormatFloat(max, 'g', -1, bits)
+	r := base[T](name, kind, "-"+exact, exact, "")
+	r.MinHuman, r.MaxHuman = "-"+humanFloat(max), humanFloat(max)
+	digits := 6
+	if bits == 64 {
+		digits = 15
+	}
+	r.Notes = fmt.Sprintf("smallest positive %s; ~%d significant digits", humanFloat(smallest), digits)
+	return r
+}
+
+func note(r Row, notes string) Row {
+	if r.Notes != "" {
+		r.Notes = notes + "; " + r.Notes
+	} else {
+		r.Notes = notes
+	}
+	return r
+}
+
+func alias(r Row, of, notes string) Row {
+	r.Alias = of
+	return note(r, "alias for "+of+", "+notes)
+}
+
+// zeroString formats a zero value the way it would be written in Go.
+func zeroString(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', 1, 64)
+	case reflect.Complex64, reflect.Complex128:
+		return fmt.Sprint(v.Complex())
+	case reflect.Interface:
+		return "nil"
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// scales are the short-scale names used by humanInt.
+var scales = []struct {
+	value  float64
+	suffix string
+}{
+	{1e18, "Qi"}, // quintillion
+	{1e15, "Qa"}, // quadrillion
+	{1e12, "T"},
+	{1e9, "B"},
+	{1e6, "M"},
+	{1e3, "K"},
+}
+
+// humanInt abbreviates n to three significant figures: 2147483647 is
+// "2.15B". Numbers below 10,000 are printed in full.
+func humanInt(n float64) string {
+	abs := math.Abs(n)
+	if abs < 1e4 {
+		return strconv.FormatFloat(n, 'f', 0, 64)
+	}
+	for _, s := range scales {
+		if abs >= s.value {
+			v := strconv.FormatFloat(n/s.value, 'g', 3, 64)
+			return v + s.suffix
+		}
+	}
+	return strconv.FormatFloat(n, 'f', 0, 64)
+}
+
+// humanFloat writes f as a short power of ten: 3.4028234663852886e+38 is
+// "3.4e38".
+func humanFloat(f float64) string {
+	mant, exp, _ := strings.Cut(strconv.FormatFloat(f, 'e', 1, 64), "e")
+	return mant + "e" + strings.TrimLeft(strings.TrimPrefix(exp, "+"), "0")
+}
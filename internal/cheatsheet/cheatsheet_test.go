@@ -0,0 +1,105 @@
+package cheatsheet
+
+import (
+	"go/types"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// Every predeclared type has a row, except any, an alias for interface{},
+// and comparable, which is only a constraint.
+func TestEveryPredeclaredType(t *testing.T) {
+	have := map[string]bool{}
+	for _, r := range Rows() {
+		have[r.Type] = true
+	}
+	for _, name := range types.Universe.Names() {
+		if _, ok := types.Universe.Lookup(name).(*types.TypeName); !ok || name == "any" || name == "comparable" {
+			continue
+		}
+		if !have[name] {
+			t.Errorf("no row for %s", name)
+		}
+	}
+}
+
+// The sizes from unsafe.Sizeof agree with what the compiler uses for the
+// architecture the test runs on.
+func TestSizes(t *testing.T) {
+	sizes := types.SizesFor("gc", runtime.GOARCH)
+	for _, r := range Rows() {
+		want := sizes.Sizeof(types.Universe.Lookup(r.Type).Type())
+		if int64(r.Size) != want {
+			t.Errorf("%s: size %d, want %d", r.Type, r.Size, want)
+		}
+	}
+}
+
+func TestRows(t *testing.T) {
+	rows := map[string]Row{}
+	for _, r := range Rows() {
+		rows[r.Type] = r
+	}
+	tests := []struct {
+		typ, zero, min, max, minHuman, maxHuman string
+	}{
+		{"int8", "0", "-128", "127", "-128", "127"},
+		{"int32", "0", "-2147483648", "2147483647", "-2.15B", "2.15B"},
+		{"uint64", "0", "0", "18446744073709551615", "0", "18.4Qi"},
+		{"float32", "0.0", "-3.4028235e+38", "3.4028235e+38", "-3.4e38", "3.4e38"},
+		{"complex128", "(0+0i)", "-1.7976931348623157e+308", "1.7976931348623157e+308", "-1.8e308", "1.8e308"},
+		{"bool", "false", "false", "true", "", ""},
+		{"string", `""`, "", "", "", ""},
+		{"error", "nil", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		r := rows[tt.typ]
+		got := []string{r.Zero, r.Min, r.Max, r.MinHuman, r.MaxHuman}
+		want := []string{tt.zero, tt.min, tt.max, tt.minHuman, tt.maxHuman}
+		if strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("%s: zero, min, max, ≈min, ≈max = %q, want %q", tt.typ, got, want)
+		}
+	}
+	if r := rows["rune"]; r.Alias != "int32" || r.Size != 4 {
+		t.Errorf("rune: alias %q, size %d; want int32, 4", r.Alias, r.Size)
+	}
+	if r := rows["byte"]; r.Alias != "uint8" || r.Size != 1 {
+		t.Errorf("byte: alias %q, size %d; want uint8, 1", r.Alias, r.Size)
+	}
+}
+
+func TestHuman(t *testing.T) {
+	for _, tt := range []struct {
+		n    float64
+		want string
+	}{
+		{0, "0"}, {9999, "9999"}, {-32768, "-32.8K"}, {65535, "65.5K"},
+		{4294967295, "4.29B"}, {1e12, "1T"}, {9223372036854775807, "9.22Qi"},
+	} {
+		if got := humanInt(tt.n); got != tt.want {
+			t.Errorf("humanInt(%v) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+	for _, tt := range []struct {
+		f    float64
+		want string
+	}{
+		{3.4028234663852886e+38, "3.4e38"}, {4.9e-324, "4.9e-324"}, {1.401298464324817e-45, "1.4e-45"},
+	} {
+		if got := humanFloat(tt.f); got != tt.want {
+			t.Errorf("humanFloat(%v) = %q, want %q", tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b strings.Builder
+	if err := WriteCSV(&b, Rows()[:1]); err != nil {
+		t.Fatal(err)
+	}
+	want := "type,size,zero,min,min_human,max,max_human,notes\nint8,1,0,-128,-128,127,127,\n"
+	if b.String() != want {
+		t.Errorf("WriteCSV:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
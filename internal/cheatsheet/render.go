@@ -0,0 +1,55 @@
+package cheatsheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+var headers = []string{"Type", "Size", "Zero", "Min", "≈Min", "Max", "≈Max", "Notes"}
+
+func (r Row) cells() []string {
+	return []string{r.Type, strconv.FormatUint(uint64(r.Size), 10), r.Zero, r.Min, r.MinHuman, r.Max, r.MaxHuman, r.Notes}
+}
+
+// WriteText writes rows as an aligned table.
+func WriteText(w io.Writer, rows []Row) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, r := range rows {
+		fmt.Fprintln(tw, strings.TrimRight(strings.Join(r.cells(), "\t"), "\t"))
+	}
+	return tw.Flush()
+}
+
+// WriteMarkdown writes rows as a Markdown table.
+func WriteMarkdown(w io.Writer, rows []Row) error {
+	fmt.Fprintf(w, "| %s |\n", strings.Join(headers, " | "))
+	fmt.Fprintf(w, "|%s\n", strings.Repeat("---|", len(headers)))
+	for _, r := range rows {
+		cells := r.cells()
+		for i, c := range cells {
+			if c != "" && i < len(cells)-1 {
+				cells[i] = "`" + c + "`"
+			}
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes rows as CSV with snake_case column names.
+func WriteCSV(w io.Writer, rows []Row) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"type", "size", "zero", "min", "min_human", "max", "max_human", "notes"})
+	for _, r := range rows {
+		cw.Write(r.cells())
+	}
+	cw.Flush()
+	return cw.Error()
+}
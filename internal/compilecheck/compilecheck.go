@@ -0,0 +1,266 @@
+// Package compilecheck checks the "observe the error" exercises: snippets
+// that students write so that they deliberately fail to compile.
+//
+// A snippet is type-checked with go/types and passes when the error the
+// exercise asks for occurs. The compiler's own messages are kept verbatim so
+// they can be shown to the student.
+package compilecheck
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Class is a kind of compile error.
+type Class string
+
+const (
+	MismatchedTypes Class = "mismatched-types" // cannot use "hello" (untyped string constant) as int value
+	Undefined       Class = "undefined"        // undefined: y
+	NoNewVariables  Class = "no-new-variables" // no new variables on left side of :=
+	Unexported      Class = "unexported"       // name foo not exported by package bar; undefined: fmt.println (but have Println)
+	Unused          Class = "unused"           // declared and not used: x
+)
+
+var classes = []struct {
+	class Class
+	re    *regexp.Regexp
+}{
+	{MismatchedTypes, regexp.MustCompile(`^(cannot use .* as .* value|invalid operation: .* \(mismatched types|cannot convert)`)},
+	{NoNewVariables, regexp.MustCompile(`^no new variables on left side of :=`)},
+	// go/types reports an unexported name it knows of as "not exported",
+	// and a selector that misses an exported name only by case as
+	// undefined with a "(but have X)" hint. Any other undefined selector,
+	// such as fmt.foo, does not exist at all.
+	{Unexported, regexp.MustCompile(`(not exported by package|^undefined: \w+\.\w+ \(but have \w+\)$)`)},
+	{Undefined, regexp.MustCompile(`^undefined: `)},
+	{Unused, regexp.MustCompile(`(declared and not used|"[^"]+" imported and not used)`)},
+}
+
+// Classify returns the class of a go/types error message, or "" when the
+// message belongs to none of the known classes.
+func Classify(msg string) Class {
+	for _, c := range classes {
+		if c.re.MatchString(msg) {
+			return c.class
+		}
+	}
+	return ""
+}
+
+// Exercise is an assignment question that expects a compile error.
+type Exercise struct {
+	ID     string // "day3/q5"
+	Source string // assignment file the question comes from
+	Prompt string
+	Want   Class
+}
+
+var exercises = []Exercise{
+	{"day3/q5", "Assignments/Day3/variables.go",
+		"Declare a variable x as int and assign it a number, then try assigning a string to x.", MismatchedTypes},
+	{"day3/q6", "Assignments/Day3/variables.go",
+		"Declare a variable using := with a string value, then try assigning an integer to it.", MismatchedTypes},
+	{"day3/q8", "Assignments/Day3/variables.go",
+		"Declare y inside a block {} and try printing it outside the block.", Undefined},
+	{"day3/q10", "Assignments/Day3/variables.go",
+		"Declare a local variable in varUse() and try accessing it from main().", Undefined},
+	{"day3/q18", "Assignments/Day3/variables.go",
+		"Declare x and y with :=, then redeclare both with := again.", NoNewVariables},
+	{"day4/q7", "Assignments/Day4/variable.go",
+		"Access an unexported identifier of another package from main.", Unexported},
+	{"day4/q11", "Assignments/Day4/variable.go",
+		"Declare x inside a for loop and try to access it after the loop.", Undefined},
+	{"day4/q17", "Assignments/Day4/variable.go",
+		"Redeclare x, y := 30, 40 when x and y already exist.", NoNewVariables},
+}
+
+// Exercises returns every known exercise in assignment order.
+func Exercises() []Exercise {
+	return append([]Exercise(nil), exercises...)
+}
+
+// Lookup returns the exercise with the given ID.
+func Lookup(id string) (Exercise, bool) {
+	id = strings.ToLower(id)
+	for _, e := range exercises {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Exercise{}, false
+}
+
+// Diagnostic is one compile error, positioned in the student's snippet.
+type Diagnostic struct {
+	Pos     token.Position
+	Message string
+	Class   Class
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Pos, d.Message)
+}
+
+// Result is the outcome of checking one snippet against an exercise.
+type Result struct {
+	Exercise Exercise
+	Passed   bool
+	Errors   []Diagnostic // every compile error, in source order
+}
+
+// Check type-checks src and reports whether it fails with the error the
+// exercise expects. filename is used in positions only.
+func (e Exercise) Check(filename string, src []byte) (*Result, error) {
+	diags, err := TypeCheck(filename, src)
+	if err != nil {
+		return nil, err
+	}
+	r := &Result{Exercise: e, Errors: diags}
+	for _, d := range diags {
+		if d.Class == e.Want {
+			r.Passed = true
+		}
+	}
+	return r, nil
+}
+
+// packages are the standard-library packages a snippet may use without
+// writing an import.
+var packages = map[string]string{
+	"errors":  "errors",
+	"fmt":     "fmt",
+	"math":    "math",
+	"os":      "os",
+	"strconv": "strconv",
+	"strings": "strings",
+	"time":    "time",
+	"unsafe":  "unsafe",
+}
+
+// TypeCheck returns every compile error in src.
+//
+// src may be a complete file, top-level declarations without a package
+// clause, or bare statements, which are wrapped in func main. Missing
+// imports of common standard-library packages are added. Positions always
+// refer to lines of src. A syntax error is returned as a Diagnostic, not an
+// error.
+func TypeCheck(filename string, src []byte) ([]Diagnostic, error) {
+	fset := token.NewFileSet()
+	f, perr := parseSnippet(fset, filename, src)
+	if f == nil {
+		var list scanner.ErrorList
+		if errors.As(perr, &list) && len(list) > 0 {
+			var diags []Diagnostic
+			for _, e := range list {
+				diags = append(diags, Diagnostic{Pos: e.Pos, Message: e.Msg})
+			}
+			return diags, nil
+		}
+		return nil, perr
+	}
+
+	var diags []Diagnostic
+	conf := types.Config{
+		Importer: importer.Default(),
+		Error: func(err error) {
+			var te types.Error
+			if errors.As(err, &te) {
+				diags = append(diags, Diagnostic{
+					Pos:     te.Fset.Position(te.Pos),
+					Message: te.Msg,
+					Class:   Classify(te.Msg),
+				})
+			}
+		},
+	}
+	conf.Check(f.Name.Name, fset, []*ast.File{f}, nil)
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := diags[i].Pos, diags[j].Pos
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return diags, nil
+}
+
+// parseSnippet parses src as a file, as declarations, or as statements,
+// in that order. //line directives keep positions relative to src.
+func parseSnippet(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+	if _, err := parser.ParseFile(token.NewFileSet(), filename, src, parser.PackageClauseOnly); err == nil {
+		return parser.ParseFile(fset, filename, src, parser.AllErrors)
+	}
+
+	wrap := func(before, after string, imports []string) []byte {
+		var b bytes.Buffer
+		b.WriteString("package main\n")
+		for _, path := range imports {
+			fmt.Fprintf(&b, "import %q\n", path)
+		}
+		b.WriteString(before)
+		fmt.Fprintf(&b, "//line %s:1:1\n", filename)
+		b.Write(src)
+		b.WriteString(after)
+		return b.Bytes()
+	}
+	parse := func(before, after string) (*ast.File, error) {
+		f, err := parser.ParseFile(fset, filename, wrap(before, after, nil), parser.AllErrors)
+		if err != nil {
+			return nil, err
+		}
+		imports := missingImports(f)
+		if len(imports) == 0 {
+			return f, nil
+		}
+		return parser.ParseFile(fset, filename, wrap(before, after, imports), parser.AllErrors)
+	}
+
+	f, declErr := parse("", "\n")
+	if declErr == nil {
+		return f, nil
+	}
+	f, stmtErr := parse("func main() {\n", "\n}\n")
+	if stmtErr == nil {
+		return f, nil
+	}
+	// Report the syntax errors of whichever reading got further.
+	if first(declErr).Line > first(stmtErr).Line {
+		return nil, declErr
+	}
+	return nil, stmtErr
+}
+
+// missingImports returns the import paths for package names that f uses
+// without importing.
+func missingImports(f *ast.File) []string {
+	seen := map[string]bool{}
+	var paths []string
+	for _, id := range f.Unresolved {
+		if path, ok := packages[id.Name]; ok && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// first returns the position of the first syntax error in err.
+func first(err error) token.Position {
+	var list scanner.ErrorList
+	if errors.As(err, &list) && len(list) > 0 {
+		return list[0].Pos
+	}
+	return token.Position{}
+}
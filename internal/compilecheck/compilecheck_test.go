@@ -0,0 +1,64 @@
+package compilecheck
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want Class
+	}{
+		{`cannot use "hello" (untyped string constant) as int value in assignment`, MismatchedTypes},
+		{`invalid operation: x + s (mismatched types int and string)`, MismatchedTypes},
+		{`undefined: y`, Undefined},
+		{`undefined: fmt.println (but have Println)`, Unexported},
+		{`name asciiSpace not exported by package strings`, Unexported},
+		{`undefined: fmt.foo`, Undefined},
+		{`undefined: fmt.Foo`, Undefined},
+		{`no new variables on left side of :=`, NoNewVariables},
+		{`declared and not used: x`, Unused},
+		{`"os" imported and not used`, Unused},
+		{`missing return`, ""},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.msg); got != tt.want {
+			t.Errorf("Classify(%q) = %q, want %q", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		id, src string
+		pass    bool
+	}{
+		{"day4/q7", `fmt.println("a")`, true},
+		{"day4/q7", `fmt.foo()`, false},
+		{"day4/q7", `fmt.Println("a")`, false},
+		{"day3/q5", "var x int = 1\nx = \"one\"\n_ = x", true},
+		{"day3/q8", "{\n\ty := 1\n\t_ = y\n}\nfmt.Println(y)", true},
+		{"day3/q18", "x, y := 1, 2\nx, y := 3, 4\n_, _ = x, y", true},
+	}
+	for _, tt := range tests {
+		e, ok := Lookup(tt.id)
+		if !ok {
+			t.Fatalf("no exercise %s", tt.id)
+		}
+		r, err := e.Check("snippet.go", []byte(tt.src))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.id, err)
+		}
+		if r.Passed != tt.pass {
+			t.Errorf("%s with %q: Passed = %v, want %v; errors %v", tt.id, tt.src, r.Passed, tt.pass, r.Errors)
+		}
+	}
+}
+
+func TestPositions(t *testing.T) {
+	diags, err := TypeCheck("snippet.go", []byte("x := 1\nfmt.println(x)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) != 1 || diags[0].Pos.Line != 2 || diags[0].Pos.Filename != "snippet.go" {
+		t.Fatalf("diagnostics = %v, want one on snippet.go line 2", diags)
+	}
+}
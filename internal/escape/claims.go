@@ -0,0 +1,214 @@
+package escape
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Claim is an expected escape decision written in the source as a
+// directive comment on the line before the code it describes:
+//
+//	//escape:heap count
+//	count := 0
+//
+//	//escape:stack
+//	result := a + b
+//
+// The optional subject names the variable or expression as the compiler
+// prints it ("count", "func literal", "make([]int, 5)"). Without one the
+// claim covers every decision on the line.
+type Claim struct {
+	Pos     Pos    `json:"pos"`  // the directive itself
+	Line    int    `json:"line"` // the line it describes
+	Want    string `json:"want"` // "heap" or "stack"
+	Subject string `json:"subject,omitempty"`
+}
+
+func (c Claim) String() string {
+	s := "//escape:" + c.Want
+	if c.Subject != "" {
+		s += " " + c.Subject
+	}
+	return s
+}
+
+// Verdict is the outcome of checking one claim.
+type Verdict struct {
+	Claim    Claim      `json:"claim"`
+	OK       bool       `json:"ok"`
+	Message  string     `json:"message"`
+	Evidence []Decision `json:"evidence,omitempty"`
+}
+
+const directive = "//escape:"
+
+// ParseClaims reads the //escape: directives in a Go source file.
+func ParseClaims(filename string) ([]Claim, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	var claims []Claim
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			rest, ok := strings.CutPrefix(c.Text, directive)
+			if !ok {
+				continue
+			}
+			p := fset.Position(c.Pos())
+			want, subject, _ := strings.Cut(strings.TrimSpace(rest), " ")
+			if want != "heap" && want != "stack" {
+				return nil, fmt.Errorf("%s: unknown claim %q, want //escape:heap or //escape:stack", p, c.Text)
+			}
+			claims = append(claims, Claim{
+				Pos:     Pos{File: filename, Line: p.Line, Column: p.Column},
+				Line:    p.Line + 1,
+				Want:    want,
+				Subject: strings.TrimSpace(subject),
+			})
+		}
+	}
+	return claims, nil
+}
+
+// Verify checks claims against an unfiltered report.
+//
+// A heap claim holds when the compiler moved the subject to the heap or
+// reported that it escapes. A stack claim holds when the compiler reported
+// that the subject does not escape and nothing on the line escapes. The
+// compiler says nothing about a local whose address is never taken, so a
+// stack claim without evidence is unverified and fails: claim what the
+// compiler reports on, such as a parameter or a make.
+func Verify(r *Report, claims []Claim) []Verdict {
+	byLine := map[string][]Decision{}
+	for _, f := range r.Funcs {
+		for _, d := range f.Decisions {
+			k := lineKey(d.Pos.File, d.Pos.Line)
+			byLine[k] = append(byLine[k], d)
+		}
+	}
+
+	var verdicts []Verdict
+	for _, c := range claims {
+		v := Verdict{Claim: c}
+		heap := false
+		for _, d := range byLine[lineKey(c.Pos.File, c.Line)] {
+			if !relevant(d) || (c.Subject != "" && d.Subject != c.Subject) {
+				continue
+			}
+			v.Evidence = append(v.Evidence, d)
+			if d.Kind == MovedToHeap || d.Kind == EscapesToHeap {
+				heap = true
+			}
+		}
+		switch {
+		case c.Want == "heap" && heap:
+			v.OK, v.Message = true, "escapes as claimed"
+		case c.Want == "heap" && len(v.Evidence) == 0:
+			v.Message = "claimed heap, but the compiler reports nothing for it on this line"
+		case c.Want == "heap":
+			v.Message = "claimed heap, but it does not escape"
+		case heap:
+			v.Message = "claimed stack, but it escapes to the heap"
+		case len(v.Evidence) == 0:
+			v.Message = "unverified: the compiler reports nothing for it on this line"
+		default:
+			v.OK, v.Message = true, "stays on the stack as claimed"
+		}
+		verdicts = append(verdicts, v)
+	}
+	return verdicts
+}
+
+// lineKey identifies a source line. File names are made absolute, since
+// the compiler's and the claims' may be relative to different directories.
+func lineKey(file string, line int) string {
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// relevant reports whether d is an escape decision rather than an
+// inlining note or a variadic-argument detail.
+func relevant(d Decision) bool {
+	switch d.Kind {
+	case MovedToHeap, EscapesToHeap, DoesNotEscape:
+		return d.Subject != "... argument"
+	}
+	return false
+}
+
+// VerifyPackage analyzes target (see Analyze) and checks every claim in
+// the package's Go files, including files the compiler reported nothing
+// about.
+func VerifyPackage(ctx context.Context, dir, target string) ([]Verdict, error) {
+	r, err := Analyze(ctx, dir, target)
+	if err != nil {
+		return nil, err
+	}
+	names, err := goFiles(ctx, dir, target)
+	if err != nil {
+		return nil, err
+	}
+	var claims []Claim
+	for _, name := range names {
+		c, err := ParseClaims(name)
+		if err != nil {
+			return nil, err
+		}
+		claims = append(claims, c...)
+	}
+	return Verify(r, claims), nil
+}
+
+// goFiles lists the Go files go build compiles for target, sorted.
+func goFiles(ctx context.Context, dir, target string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "go", "list", "-f", `{{range .GoFiles}}{{$.Dir}}{{"/"}}{{.}}{{"\n"}}{{end}}`, target)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list %s: %v\n%s", target, err, stderr.Bytes())
+	}
+	var names []string
+	for _, name := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if name != "" {
+			names = append(names, filepath.FromSlash(name))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// WriteVerdicts writes one line per claim, with the compiler's decisions
+// under every claim that failed, and returns the number of failures.
+func WriteVerdicts(w io.Writer, verdicts []Verdict) (int, error) {
+	failed := 0
+	for _, v := range verdicts {
+		status := "ok  "
+		if !v.OK {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Fprintf(w, "%s %s:%d: %s: %s\n", status, filepath.Base(v.Claim.Pos.File), v.Claim.Line, v.Claim, v.Message)
+		if v.OK {
+			continue
+		}
+		for _, d := range v.Evidence {
+			fmt.Fprintf(w, "       %d:%d %s\n", d.Pos.Line, d.Pos.Column, d.summary())
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d claims, %d failed\n", len(verdicts), failed)
+	return failed, err
+}
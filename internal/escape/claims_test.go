@@ -0,0 +1,61 @@
+package escape
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestLessonClaims fails when the compiler in use no longer agrees with an
+// //escape: claim in the escape analysis lesson.
+func TestLessonClaims(t *testing.T) {
+	verdicts, err := VerifyPackage(context.Background(), "../..", "./Codes/Day4/07_EscapeAnalysis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verdicts) == 0 {
+		t.Fatal("no //escape: claims found in the lesson")
+	}
+	for _, v := range verdicts {
+		if v.OK {
+			continue
+		}
+		var got []string
+		for _, d := range v.Evidence {
+			got = append(got, d.summary())
+		}
+		t.Errorf("escape.go:%d: %s: %s; compiler: %s", v.Claim.Line, v.Claim, v.Message, strings.Join(got, "; "))
+	}
+}
+
+func TestVerify(t *testing.T) {
+	at := func(line, col int) Pos { return Pos{File: "a.go", Line: line, Column: col} }
+	r := &Report{Funcs: []*Func{{Name: "f", Decisions: []Decision{
+		{Pos: at(3, 2), Kind: MovedToHeap, Subject: "x"},
+		{Pos: at(5, 8), Kind: DoesNotEscape, Subject: "make([]int, 5)"},
+		{Pos: at(7, 8), Kind: CanInline, Subject: "g"},
+	}}}}
+	claim := func(line int, want, subject string) Claim {
+		return Claim{Pos: at(line-1, 2), Line: line, Want: want, Subject: subject}
+	}
+	tests := []struct {
+		claim Claim
+		ok    bool
+		msg   string
+	}{
+		{claim(3, "heap", "x"), true, "escapes as claimed"},
+		{claim(3, "heap", ""), true, "escapes as claimed"},
+		{claim(3, "stack", "x"), false, "claimed stack, but it escapes to the heap"},
+		{claim(3, "heap", "y"), false, "claimed heap, but the compiler reports nothing for it on this line"},
+		{claim(5, "stack", "make([]int, 5)"), true, "stays on the stack as claimed"},
+		{claim(5, "heap", ""), false, "claimed heap, but it does not escape"},
+		{claim(5, "stack", "buf"), false, "unverified: the compiler reports nothing for it on this line"},
+		{claim(7, "stack", ""), false, "unverified: the compiler reports nothing for it on this line"},
+	}
+	for _, tt := range tests {
+		v := Verify(r, []Claim{tt.claim})[0]
+		if v.OK != tt.ok || v.Message != tt.msg {
+			t.Errorf("line %d %s: OK %v, %q; want %v, %q", tt.claim.Line, tt.claim, v.OK, v.Message, tt.ok, tt.msg)
+		}
+	}
+}
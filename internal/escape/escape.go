@@ -0,0 +1,326 @@
+// Package escape runs the compiler's escape analysis (-gcflags=-m=2) on a
+// package and turns its diagnostics into a structured per-function report:
+// which variables moved to the heap and why, which parameters leak, and
+// which functions and calls were inlined.
+package escape
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kind classifies a compiler decision.
+type Kind string
+
+const (
+	MovedToHeap   Kind = "moved-to-heap"   // moved to heap: x
+	EscapesToHeap Kind = "escapes"         // make([]int, n) escapes to heap
+	DoesNotEscape Kind = "does-not-escape" // data does not escape
+	LeakingParam  Kind = "leaking-param"   // leaking param: b
+	Captured      Kind = "captured"        // f capturing by value: x
+	CanInline     Kind = "can-inline"      // can inline f with cost 9
+	CannotInline  Kind = "cannot-inline"   // cannot inline main: function too complex
+	InlinedCall   Kind = "inlined-call"    // inlining call to fmt.Println
+)
+
+// Pos is a source position.
+type Pos struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Decision is one diagnostic from the compiler.
+type Decision struct {
+	Pos     Pos      `json:"pos"`
+	Func    string   `json:"func"` // enclosing function, e.g. "createCounter" or "(*T).M"
+	Kind    Kind     `json:"kind"`
+	Subject string   `json:"subject"`          // variable, expression, parameter or callee
+	Detail  string   `json:"detail,omitempty"` // inlining cost or reason, capture mode
+	Reason  []string `json:"reason,omitempty"` // -m=2 flow explanation, for escapes and leaks
+}
+
+// Func collects the decisions made inside one function.
+type Func struct {
+	Name      string     `json:"name"`
+	Pos       Pos        `json:"pos"`
+	Decisions []Decision `json:"decisions"`
+}
+
+// Report is the escape analysis of one package.
+type Report struct {
+	Package string  `json:"package"`
+	Funcs   []*Func `json:"funcs"`
+}
+
+// Analyze builds target with -gcflags=-m=2 from dir and parses the result.
+// target is anything `go build` accepts: a package path, a directory such
+// as ./Codes/Day4/07_EscapeAnalysis, or a single .go file.
+func Analyze(ctx context.Context, dir, target string) (*Report, error) {
+	cmd := exec.CommandContext(ctx, "go", "build", "-gcflags=-m=2", "-o", os.DevNull, target)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("go build %s: %v\n%s", target, err, stderr.Bytes())
+	}
+	return Parse(dir, stderr.Bytes())
+}
+
+var (
+	lineRE = regexp.MustCompile(`^(.+?\.go):(\d+):(\d+): (.*)$`)
+
+	explainRE   = regexp.MustCompile(`^(.+) escapes to heap in (\S+):$`)
+	leakHeadRE  = regexp.MustCompile(`^parameter (\S+) leaks to (.+) for (\S+) with derefs=(-?\d+):$`)
+	movedRE     = regexp.MustCompile(`^moved to heap: (\S+)$`)
+	escapesRE   = regexp.MustCompile(`^(.+) escapes to heap$`)
+	noEscapeRE  = regexp.MustCompile(`^(.+) does not escape$`)
+	leakingRE   = regexp.MustCompile(`^leaking param( content)?: (\S+)(.*)$`)
+	captureRE   = regexp.MustCompile(`^(\S+) capturing by (value|ref): (\S+)`)
+	canInlineRE = regexp.MustCompile(`^can inline (\S+) with cost (\d+)`)
+	cantInline  = regexp.MustCompile(`^cannot inline (\S+): (.*)$`)
+	inliningRE  = regexp.MustCompile(`^inlining call to (\S+)`)
+)
+
+// Parse parses -m=2 compiler output. Relative file names are resolved
+// against dir so the source can be read to attribute decisions to
+// functions.
+func Parse(dir string, out []byte) (*Report, error) {
+	r := &Report{}
+	var decisions []Decision
+	reasons := map[string][]string{} // "pos|subject" -> flow lines
+	var explaining string            // key of the explanation being read
+
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "# ") {
+			r.Package = strings.TrimPrefix(line, "# ")
+			continue
+		}
+		m := lineRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		ln, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		file := m[1]
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		pos := Pos{File: file, Line: ln, Column: col}
+		msg := m[4]
+
+		// Indented lines continue the current -m=2 explanation.
+		if strings.HasPrefix(msg, " ") {
+			if explaining != "" {
+				reasons[explaining] = append(reasons[explaining], strings.TrimSpace(msg))
+			}
+			continue
+		}
+		explaining = ""
+
+		if e := explainRE.FindStringSubmatch(msg); e != nil {
+			explaining = key(pos, e[1])
+			continue
+		}
+		if e := leakHeadRE.FindStringSubmatch(msg); e != nil {
+			explaining = key(pos, e[1])
+			reasons[explaining] = append(reasons[explaining], "leaks to "+e[2]+" with derefs="+e[4])
+			continue
+		}
+
+		d := Decision{Pos: pos}
+		switch {
+		case movedRE.MatchString(msg):
+			d.Kind, d.Subject = MovedToHeap, movedRE.FindStringSubmatch(msg)[1]
+		case leakingRE.MatchString(msg):
+			e := leakingRE.FindStringSubmatch(msg)
+			d.Kind, d.Subject, d.Detail = LeakingParam, e[2], strings.TrimSpace(e[3])
+			if e[1] != "" {
+				d.Detail = strings.TrimSpace("content " + d.Detail)
+			}
+		case captureRE.MatchString(msg):
+			e := captureRE.FindStringSubmatch(msg)
+			d.Kind, d.Subject, d.Detail = Captured, e[3], "by "+e[2]
+		case canInlineRE.MatchString(msg):
+			e := canInlineRE.FindStringSubmatch(msg)
+			d.Kind, d.Subject, d.Detail = CanInline, e[1], "cost "+e[2]
+		case cantInline.MatchString(msg):
+			e := cantInline.FindStringSubmatch(msg)
+			d.Kind, d.Subject, d.Detail = CannotInline, e[1], e[2]
+		case inliningRE.MatchString(msg):
+			d.Kind, d.Subject = InlinedCall, inliningRE.FindStringSubmatch(msg)[1]
+		case escapesRE.MatchString(msg):
+			d.Kind, d.Subject = EscapesToHeap, escapesRE.FindStringSubmatch(msg)[1]
+		case noEscapeRE.MatchString(msg):
+			d.Kind, d.Subject = DoesNotEscape, noEscapeRE.FindStringSubmatch(msg)[1]
+		default:
+			continue
+		}
+		decisions = append(decisions, d)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range decisions {
+		d := &decisions[i]
+		switch d.Kind {
+		case MovedToHeap, EscapesToHeap, LeakingParam:
+			d.Reason = reasons[key(d.Pos, d.Subject)]
+		}
+	}
+	if err := r.group(decisions); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func key(pos Pos, subject string) string {
+	return pos.String() + "|" + subject
+}
+
+// group attributes decisions to the function declarations enclosing them.
+func (r *Report) group(decisions []Decision) error {
+	funcs := map[string][]funcSpan{}
+	byName := map[string]*Func{}
+	for i := range decisions {
+		d := &decisions[i]
+		spans, ok := funcs[d.Pos.File]
+		if !ok {
+			var err error
+			spans, err = funcSpans(d.Pos.File)
+			if err != nil {
+				return err
+			}
+			funcs[d.Pos.File] = spans
+		}
+		// Decisions outside every function, in package-level variable
+		// initialisers, are grouped per file and placed at the first.
+		name, k, start := "(package scope)", "(package scope) "+d.Pos.File, Pos{}
+		for _, s := range spans {
+			if d.Pos.Line >= s.start.Line && d.Pos.Line <= s.end {
+				name, k, start = s.name, s.name, s.start
+				break
+			}
+		}
+		d.Func = name
+		f := byName[k]
+		if f == nil {
+			f = &Func{Name: name, Pos: start}
+			byName[k] = f
+			r.Funcs = append(r.Funcs, f)
+		}
+		f.Decisions = append(f.Decisions, *d)
+	}
+	for _, f := range r.Funcs {
+		sort.SliceStable(f.Decisions, func(i, j int) bool {
+			a, b := f.Decisions[i].Pos, f.Decisions[j].Pos
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.Column < b.Column
+		})
+		if f.Pos.File == "" {
+			f.Pos = f.Decisions[0].Pos
+		}
+	}
+	sort.SliceStable(r.Funcs, func(i, j int) bool {
+		a, b := r.Funcs[i].Pos, r.Funcs[j].Pos
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return nil
+}
+
+type funcSpan struct {
+	name  string
+	start Pos
+	end   int // last line
+}
+
+func funcSpans(filename string) ([]funcSpan, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	var spans []funcSpan
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		start := fset.Position(fn.Pos())
+		spans = append(spans, funcSpan{
+			name:  funcName(fn),
+			start: Pos{File: filename, Line: start.Line, Column: start.Column},
+			end:   fset.Position(fn.End()).Line,
+		})
+	}
+	return spans, nil
+}
+
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	t := fn.Recv.List[0].Type
+	if idx, ok := t.(*ast.IndexExpr); ok {
+		t = idx.X
+	}
+	switch t := t.(type) {
+	case *ast.StarExpr:
+		if id, ok := t.X.(*ast.Ident); ok {
+			return "(*" + id.Name + ")." + fn.Name.Name
+		}
+	case *ast.Ident:
+		return t.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// Filter returns a copy of r keeping only decisions for which keep is true.
+// Functions left without decisions are dropped.
+func (r *Report) Filter(keep func(Decision) bool) *Report {
+	out := &Report{Package: r.Package}
+	for _, f := range r.Funcs {
+		nf := &Func{Name: f.Name, Pos: f.Pos}
+		for _, d := range f.Decisions {
+			if keep(d) {
+				nf.Decisions = append(nf.Decisions, d)
+			}
+		}
+		if len(nf.Decisions) > 0 {
+			out.Funcs = append(out.Funcs, nf)
+		}
+	}
+	return out
+}
+
+// Essential reports whether d is worth showing by default. It drops
+// inlined calls and the "... argument does not escape" lines the compiler
+// emits for every variadic call.
+func Essential(d Decision) bool {
+	return d.Kind != InlinedCall && !(d.Kind == DoesNotEscape && d.Subject == "... argument")
+}
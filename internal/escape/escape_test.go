@@ -0,0 +1,80 @@
+package escape
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestParse reads -m=2 output captured from testdata/lesson.
+func TestParse(t *testing.T) {
+	out, err := os.ReadFile("testdata/lesson/m2.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Parse("testdata/lesson", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Package != "github.com/ALS240/GoTrainings/internal/escape/testdata/lesson" {
+		t.Errorf("Package = %q", r.Package)
+	}
+	var funcs []string
+	for _, f := range r.Funcs {
+		funcs = append(funcs, fmt.Sprintf("%s:%d", f.Name, f.Pos.Line))
+	}
+	want := "(package scope):10 add:12 newPoint:17 counter:22 sum:30 main:38"
+	if got := strings.Join(funcs, " "); got != want {
+		t.Errorf("funcs = %s, want %s", got, want)
+	}
+
+	find := func(fn string, kind Kind, subject string) *Decision {
+		for _, f := range r.Funcs {
+			for i, d := range f.Decisions {
+				if f.Name == fn && d.Kind == kind && d.Subject == subject {
+					return &f.Decisions[i]
+				}
+			}
+		}
+		t.Errorf("%s: no %s decision for %s", fn, kind, subject)
+		return &Decision{}
+	}
+	if d := find("newPoint", MovedToHeap, "p"); d.Pos.Line != 18 || len(d.Reason) != 3 || d.Reason[0] != "flow: ~r0 ← &p:" {
+		t.Errorf("moved to heap: p at line %d, reason %q", d.Pos.Line, d.Reason)
+	}
+	if d := find("counter", Captured, "count"); d.Detail != "by ref" {
+		t.Errorf("captured count: detail %q, want by ref", d.Detail)
+	}
+	if d := find("add", CanInline, "add"); d.Detail != "cost 9" {
+		t.Errorf("can inline add: detail %q, want cost 9", d.Detail)
+	}
+	if d := find("main", CannotInline, "main"); !strings.HasPrefix(d.Detail, "function too complex") {
+		t.Errorf("cannot inline main: detail %q", d.Detail)
+	}
+	find("sum", DoesNotEscape, "nums")
+	find("main", DoesNotEscape, "make([]int, 5)")
+	find("main", InlinedCall, "newPoint")
+	if d := find("(package scope)", EscapesToHeap, "&point{}"); d.Pos.Line != 10 || len(d.Reason) == 0 {
+		t.Errorf("&point{} escapes at line %d, reason %q", d.Pos.Line, d.Reason)
+	}
+}
+
+// The package scope is shown where its first decision is, not at line 0.
+func TestWriteTablePackageScope(t *testing.T) {
+	out, err := os.ReadFile("testdata/lesson/m2.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Parse("testdata/lesson", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := r.WriteTable(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "(package scope) (lesson.go:10)") {
+		t.Errorf("WriteTable:\n%s\nwant the package scope at lesson.go:10", b.String())
+	}
+}
@@ -0,0 +1,129 @@
+package escape
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+// summary is the one-line form of a decision used by both renderings.
+func (d Decision) summary() string {
+	var s string
+	switch d.Kind {
+	case MovedToHeap:
+		s = "moved to heap: " + d.Subject
+	case EscapesToHeap:
+		s = d.Subject + " escapes to heap"
+	case DoesNotEscape:
+		s = d.Subject + " does not escape"
+	case LeakingParam:
+		s = "leaking param: " + d.Subject
+	case Captured:
+		s = "captures " + d.Subject
+	case CanInline:
+		s = "can inline " + d.Subject
+	case CannotInline:
+		s = "cannot inline " + d.Subject
+	case InlinedCall:
+		s = "inlined call to " + d.Subject
+	}
+	if d.Detail != "" {
+		s += " (" + d.Detail + ")"
+	}
+	return s
+}
+
+// WriteTable writes one section per function with a row per decision.
+// The first line of each -m=2 explanation is shown as the reason.
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if r.Package != "" {
+		fmt.Fprintf(tw, "package %s\n", r.Package)
+	}
+	for _, f := range r.Funcs {
+		fmt.Fprintf(tw, "\n%s (%s:%d)\n", f.Name, filepath.Base(f.Pos.File), f.Pos.Line)
+		for _, d := range f.Decisions {
+			fmt.Fprintf(tw, "  %d:%d\t%s\t%s", d.Pos.Line, d.Pos.Column, d.Kind, subjectAndDetail(d))
+			if len(d.Reason) > 0 {
+				fmt.Fprintf(tw, "\t%s", strings.TrimSuffix(d.Reason[0], ":"))
+			}
+			fmt.Fprintln(tw)
+		}
+	}
+	return tw.Flush()
+}
+
+func subjectAndDetail(d Decision) string {
+	if d.Detail == "" {
+		return d.Subject
+	}
+	return d.Subject + " (" + d.Detail + ")"
+}
+
+// WriteSource writes each source file that has decisions, with every
+// decision printed under the line it refers to and its full -m=2
+// explanation indented below that.
+func (r *Report) WriteSource(w io.Writer) error {
+	byFile := map[string]map[int][]Decision{}
+	var files []string
+	for _, f := range r.Funcs {
+		for _, d := range f.Decisions {
+			lines := byFile[d.Pos.File]
+			if lines == nil {
+				lines = map[int][]Decision{}
+				byFile[d.Pos.File] = lines
+				files = append(files, d.Pos.File)
+			}
+			lines[d.Pos.Line] = append(lines[d.Pos.Line], d)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(bw, "==> %s <==\n", file)
+		for i, line := range strings.Split(strings.TrimRight(string(src), "\n"), "\n") {
+			fmt.Fprintf(bw, "%4d  %s\n", i+1, strings.ReplaceAll(line, "\t", "    "))
+			for _, d := range byFile[file][i+1] {
+				marker := strings.Repeat(" ", columnOf(line, d.Pos.Column)) + "^ "
+				fmt.Fprintf(bw, "      %s%s\n", marker, d.summary())
+				for _, reason := range d.Reason {
+					fmt.Fprintf(bw, "      %s    %s\n", strings.Repeat(" ", columnOf(line, d.Pos.Column)), reason)
+				}
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// columnOf converts a 1-based byte column in line to a display offset,
+// counting a tab as four columns as the listing does.
+func columnOf(line string, col int) int {
+	n := 0
+	for i, r := range line {
+		if i >= col-1 {
+			break
+		}
+		if r == '\t' {
+			n += 4
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,42 @@
+// Package main is the source of m2.txt, the -m=2 output Parse is tested
+// on. Regenerate it from this directory with
+//
+//	go build -gcflags=-m=2 -o /dev/null . 2> m2.txt
+package main
+
+type point struct{ X, Y int }
+
+// registry is built at package scope, outside any function.
+var registry = map[string]*point{"origin": {}}
+
+func add(a, b int) int {
+	result := a + b
+	return result
+}
+
+func newPoint(x, y int) *point {
+	p := point{x, y}
+	return &p
+}
+
+func counter() func() int {
+	count := 0
+	return func() int {
+		count++
+		return count
+	}
+}
+
+func sum(nums []int) int {
+	total := 0
+	for _, n := range nums {
+		total += n
+	}
+	return total
+}
+
+func main() {
+	next := counter()
+	registry["p"] = newPoint(add(1, 2), next())
+	registry["q"].X = sum(make([]int, 5))
+}
@@ -0,0 +1,239 @@
+// Package grader scores a student's copy of an assignment by running a
+// hidden table-driven test suite against the stub functions it ships with.
+//
+// The submission is copied into a scratch module together with the suite's
+// _test.go file and compiled once with `go test -c`; each question's test
+// then runs in its own process under `go tool test2json`. Each question
+// maps to one top-level test; a question earns its points only when every
+// case passes.
+package grader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed suites/*.txt
+var suiteFiles embed.FS
+
+// Question is one gradable question of an assignment.
+type Question struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Test   string `json:"test"` // top-level test in the hidden suite
+	Points int    `json:"points"`
+}
+
+// Suite is a hidden test suite for one assignment file.
+type Suite struct {
+	Name       string
+	Assignment string // path of the assignment inside the repository
+	Questions  []Question
+	file       string // suite file in suites/
+}
+
+var suites = map[string]*Suite{
+	"day9": {
+		Name:       "day9",
+		Assignment: "Assignments/Day9/assignment.go",
+		file:       "suites/day9_test.go.txt",
+		Questions: []Question{
+			{1, "Simple Add Function (add)", "TestQ01Add", 1},
+			{2, "Even or Odd (isEven)", "TestQ02IsEven", 1},
+			{3, "Recursive Countdown (countdown)", "TestQ03Countdown", 1},
+			{4, "Recursive Factorial (factorial)", "TestQ04Factorial", 1},
+			{5, "Variadic Sum (sum)", "TestQ05Sum", 1},
+			{6, "Function as Parameter (apply)", "TestQ06Apply", 1},
+			{7, "Anonymous Function (main)", "TestQ07AnonymousFunction", 1},
+			{8, "Multiple Return Values (divide)", "TestQ08Divide", 1},
+			{9, "Closure (makeAdder)", "TestQ09MakeAdder", 1},
+			{10, "Simple Map Function (doubleSlice)", "TestQ10DoubleSlice", 1},
+		},
+	},
+}
+
+// Lookup returns the suite registered under name.
+func Lookup(name string) (*Suite, bool) {
+	s, ok := suites[name]
+	return s, ok
+}
+
+// Names returns the names of all registered suites.
+func Names() []string {
+	names := make([]string, 0, len(suites))
+	for name := range suites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Timeout bounds the test run of each question, so an unbounded
+// recursion in countdown or factorial costs that question only and cannot
+// stall a whole cohort. A submission may take Timeout once per question;
+// the context passed to Grade bounds the whole run.
+var Timeout = 30 * time.Second
+
+// Grade runs the suite against the submission at path.
+// A submission that does not compile (for example because a signature was
+// changed) is not an error: it is reported with BuildError set and a score
+// of zero.
+func (s *Suite) Grade(ctx context.Context, path string) (*Report, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tests, err := suiteFiles.ReadFile(s.file)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "grader-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string][]byte{
+		"go.mod":         []byte("module submission\n\ngo 1.24\n"),
+		"submission.go":  src,
+		"grader_test.go": tests,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &Report{File: path, Suite: s.Name}
+
+	// Compile once so build errors can be shown verbatim to the student,
+	// then run each question in its own process: a stack overflow in
+	// factorial must not cost the student every question after it.
+	bin := filepath.Join(dir, "suite.test")
+	build := goCmd(ctx, dir, "test", "-c", "-o", bin, ".")
+	if out, err := build.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		r.BuildError = strings.TrimSpace(string(out))
+		r.score(s.Questions, nil)
+		return r, nil
+	}
+
+	events := map[string][]*caseResult{}
+	for _, q := range s.Questions {
+		run := goCmd(ctx, dir, "tool", "test2json", "-t", bin,
+			"-test.v=test2json", "-test.run", "^"+q.Test+"$", "-test.timeout", Timeout.String())
+		out, err := run.Output()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			// A failing test also exits non-zero; only a missing event
+			// stream means the test binary could not run at all.
+			if len(out) == 0 {
+				return nil, fmt.Errorf("grader: running %s: %w", q.Test, err)
+			}
+		}
+		if err := parseEvents(out, events); err != nil {
+			return nil, err
+		}
+	}
+	r.score(s.Questions, events)
+	return r, nil
+}
+
+func goCmd(ctx context.Context, dir string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+	return cmd
+}
+
+// testEvent is the subset of test2json output the grader needs.
+type testEvent struct {
+	Action string
+	Test   string
+	Output string
+}
+
+// caseResult is the outcome of one subtest, keyed by its full name.
+type caseResult struct {
+	name    string
+	passed  bool
+	crashed bool
+	output  []string
+}
+
+// maxOutput caps the lines kept per failing case; a crash would otherwise
+// paste a full goroutine dump into the report.
+const maxOutput = 8
+
+// parseEvents adds the subtests in a test2json stream to byTest, grouped by
+// top-level test. Subtests that never report pass or fail (the binary
+// crashed or timed out while they ran) count as failed.
+func parseEvents(data []byte, byTest map[string][]*caseResult) error {
+	cases := map[string]*caseResult{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var ev testEvent
+		if err := json.Unmarshal(line, &ev); err != nil {
+			return fmt.Errorf("grader: decoding test event: %w", err)
+		}
+		top, sub, ok := strings.Cut(ev.Test, "/")
+		if !ok || sub == "" {
+			continue
+		}
+		c := cases[ev.Test]
+		if c == nil {
+			c = &caseResult{name: sub}
+			cases[ev.Test] = c
+			byTest[top] = append(byTest[top], c)
+		}
+		switch ev.Action {
+		case "pass":
+			c.passed = true
+		case "output":
+			if c.crashed || len(c.output) == maxOutput {
+				break
+			}
+			if msg := failureLine(ev.Output); msg != "" {
+				c.output = append(c.output, msg)
+				c.crashed = strings.HasPrefix(msg, "fatal error:") || strings.HasPrefix(msg, "panic: test timed out")
+			}
+		}
+	}
+	return sc.Err()
+}
+
+// failureLine strips go test framing from an output line, keeping only the
+// messages written by t.Errorf and friends.
+func failureLine(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.HasPrefix(s, "=== ") || strings.HasPrefix(s, "--- ") {
+		return ""
+	}
+	// "grader_test.go:64: add(2, 3) = 0, want 5" -> "add(2, 3) = 0, want 5"
+	if i := strings.Index(s, ".go:"); i >= 0 {
+		if j := strings.Index(s[i+4:], ": "); j >= 0 {
+			s = s[i+4+j+2:]
+		}
+	}
+	return s
+}
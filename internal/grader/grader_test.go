@@ -0,0 +1,70 @@
+package grader
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// Grading runs go test on the fixtures, so these tests skip with -short.
+
+func grade(t *testing.T, fixture string) *Report {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("runs go test on a fixture submission")
+	}
+	s, ok := Lookup("day9")
+	if !ok {
+		t.Fatal("no day9 suite")
+	}
+	r, err := s.Grade(context.Background(), "testdata/day9/"+fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestGradePartial(t *testing.T) {
+	r := grade(t, "partial.go")
+	if r.BuildError != "" {
+		t.Fatalf("BuildError = %q", r.BuildError)
+	}
+	if r.Score != 4 || r.MaxScore != 10 {
+		t.Errorf("score %d/%d, want 4/10", r.Score, r.MaxScore)
+	}
+	tests := []struct {
+		number, score, passed, total int
+		failure                      string // contained in the first failure
+	}{
+		{1, 1, 4, 4, ""},
+		{2, 1, 5, 5, ""},
+		{3, 0, 1, 3, `countdown(3) printed "", want "3\n2\n1"`},
+		{4, 0, 4, 5, "factorial(0) = 0, want 1"},
+		{5, 1, 4, 4, ""},
+		{7, 1, 1, 1, ""},
+		{9, 0, 0, 4, "panic: runtime error: invalid memory address or nil pointer dereference"},
+	}
+	for _, tt := range tests {
+		res := r.Results[tt.number-1]
+		if res.Number != tt.number || res.Score != tt.score || res.Passed != tt.passed || res.Total != tt.total {
+			t.Errorf("Q%d: score %d, cases %d/%d; want %d, %d/%d",
+				res.Number, res.Score, res.Passed, res.Total, tt.score, tt.passed, tt.total)
+		}
+		switch {
+		case tt.failure == "" && len(res.Failures) > 0:
+			t.Errorf("Q%d: unexpected failures %q", tt.number, res.Failures)
+		case tt.failure != "" && (len(res.Failures) == 0 || !strings.Contains(res.Failures[0], tt.failure)):
+			t.Errorf("Q%d: failures %q, want the first to contain %q", tt.number, res.Failures, tt.failure)
+		}
+	}
+}
+
+func TestGradeBuildError(t *testing.T) {
+	r := grade(t, "broken.go")
+	if !strings.Contains(r.BuildError, "not enough arguments in call to add") {
+		t.Errorf("BuildError = %q, want the compiler's message", r.BuildError)
+	}
+	if r.Score != 0 || r.MaxScore != 10 || len(r.Results) != 10 {
+		t.Errorf("score %d/%d with %d results, want 0/10 with 10", r.Score, r.MaxScore, len(r.Results))
+	}
+}
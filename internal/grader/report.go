@@ -0,0 +1,88 @@
+package grader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Result is the outcome of one question.
+type Result struct {
+	Question
+	Passed   int      `json:"passed"`
+	Total    int      `json:"total"`
+	Score    int      `json:"score"`
+	Failures []string `json:"failures,omitempty"`
+}
+
+// Report is the per-question score report for one submission.
+type Report struct {
+	File       string   `json:"file"`
+	Suite      string   `json:"suite"`
+	BuildError string   `json:"buildError,omitempty"`
+	Results    []Result `json:"results"`
+	Score      int      `json:"score"`
+	MaxScore   int      `json:"maxScore"`
+}
+
+func (r *Report) score(questions []Question, events map[string][]*caseResult) {
+	r.Results = r.Results[:0]
+	r.Score, r.MaxScore = 0, 0
+	for _, q := range questions {
+		res := Result{Question: q}
+		for _, c := range events[q.Test] {
+			res.Total++
+			if c.passed {
+				res.Passed++
+				continue
+			}
+			if len(c.output) == 0 {
+				res.Failures = append(res.Failures, c.name+": did not finish (crash or timeout)")
+				continue
+			}
+			res.Failures = append(res.Failures, strings.Join(c.output, "\n    "))
+		}
+		if res.Total > 0 && res.Passed == res.Total {
+			res.Score = q.Points
+		}
+		if r.BuildError == "" && res.Total == 0 {
+			res.Failures = append(res.Failures, "not run")
+		}
+		r.Score += res.Score
+		r.MaxScore += q.Points
+		r.Results = append(r.Results, res)
+	}
+}
+
+// WriteText writes a human-readable report.
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (suite %s): %d/%d\n", r.File, r.Suite, r.Score, r.MaxScore)
+	if r.BuildError != "" {
+		b.WriteString("  submission does not compile; were the function signatures changed?\n")
+		for _, line := range strings.Split(r.BuildError, "\n") {
+			fmt.Fprintf(&b, "    %s\n", line)
+		}
+	}
+	for _, res := range r.Results {
+		mark := "FAIL"
+		if res.Score == res.Points && res.Total > 0 {
+			mark = "ok"
+		}
+		fmt.Fprintf(&b, "  Q%-2d %-4s %d/%d  %-36s cases %d/%d\n",
+			res.Number, mark, res.Score, res.Points, res.Title, res.Passed, res.Total)
+		for _, f := range res.Failures {
+			fmt.Fprintf(&b, "        %s\n", f)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes reports as an indented JSON array.
+func WriteJSON(w io.Writer, reports []*Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(reports)
+}
@@ -0,0 +1,54 @@
+package main
+
+// A partly solved Day 9 assignment: add, isEven, sum and the anonymous
+// function are right, factorial forgets its base case for 0 and the rest
+// are still the stubs.
+
+import "fmt"
+
+func add(a int, b int) int {
+	return a + b
+}
+
+func isEven(n int) bool {
+	return n%2 == 0
+}
+
+func countdown(n int) {
+}
+
+func factorial(n int) int {
+	if n <= 1 {
+		return n
+	}
+	return n * factorial(n-1)
+}
+
+func sum(nums ...int) int {
+	total := 0
+	for _, n := range nums {
+		total += n
+	}
+	return total
+}
+
+func apply(f func(int) int, x int) int {
+	return 0
+}
+
+func divide(a int, b int) (int, int) {
+	return 0, 0
+}
+
+func makeAdder(x int) func(int) int {
+	return nil
+}
+
+func doubleSlice(slice []int) []int {
+	return nil
+}
+
+func main() {
+	multiply := func(a, b int) int { return a * b }
+	fmt.Println(multiply(3, 4))
+}
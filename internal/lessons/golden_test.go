@@ -0,0 +1,100 @@
+package lessons
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/golden from the lessons' current output")
+
+// mask replaces output that legitimately changes from run to run.
+type mask struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+// masks lists the non-deterministic parts of each lesson's output, keyed
+// by lesson ID.
+var masks = map[string][]mask{
+	// Sizes and limits of int, uint, uintptr, string and error are those
+	// of the architecture the lesson runs on.
+	"day4/cheatsheet": {
+		{regexp.MustCompile(`(?m)^(int|uint|uintptr|string|error) .*$`), "$1  <depends on GOARCH>"},
+	},
+	// The tagless-switch greeting depends on time.Now().Hour().
+	"day6/switch": {
+		{regexp.MustCompile(`(?m)^(Morning|Afternoon|Good Evening)$`), "<greeting>"},
+	},
+}
+
+// TestGolden runs every lesson and compares its standard output with
+// testdata/golden/<day>_<name>.golden. Run with -update to accept new output.
+func TestGolden(t *testing.T) {
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := Index(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) == 0 {
+		t.Fatal("no lessons found under Codes/")
+	}
+	for _, l := range all {
+		t.Run(l.ID, func(t *testing.T) {
+			t.Parallel()
+			var stdout, stderr bytes.Buffer
+			if err := l.Run(context.Background(), root, &stdout, &stderr); err != nil {
+				t.Fatalf("go run ./%s: %v\n%s", l.Dir, err, stderr.Bytes())
+			}
+			got := stdout.String()
+			for _, m := range masks[l.ID] {
+				got = m.re.ReplaceAllString(got, m.repl)
+			}
+
+			golden := filepath.Join("testdata", "golden", strings.ReplaceAll(l.ID, "/", "_")+".golden")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("output of %s differs from %s:\n%s", l.Dir, golden, firstDiff(string(want), got))
+			}
+		})
+	}
+}
+
+// firstDiff describes the first line where got departs from want.
+func firstDiff(want, got string) string {
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(w) || i < len(g); i++ {
+		var wl, gl string
+		if i < len(w) {
+			wl = w[i]
+		}
+		if i < len(g) {
+			gl = g[i]
+		}
+		if wl != gl || i >= len(w) || i >= len(g) {
+			return fmt.Sprintf("line %d:\n  want: %q\n   got: %q", i+1, wl, gl)
+		}
+	}
+	return "(no line differs; check trailing newlines)"
+}
@@ -0,0 +1,358 @@
+// Package lessons indexes the lesson programs under Codes/ and runs them.
+//
+// A lesson is either a numbered directory such as
+// Codes/Day7/04_BitwiseOperators, or a single file directly under a day
+// such as Codes/Day8/loops.go. Every lesson is its own package main.
+package lessons
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Lesson is one runnable lesson program.
+type Lesson struct {
+	Day   int      // 7 for Codes/Day7
+	Order int      // 4 for 04_BitwiseOperators; 0 for single-file days
+	Name  string   // "04_BitwiseOperators" or "loops"
+	ID    string   // "day7/bitwiseoperators"
+	Dir   string   // package directory, relative to the repository root
+	Files []string // Go files, relative to Dir
+	Topic string   // headline taken from the lesson's own comments or output
+}
+
+var (
+	dayDir    = regexp.MustCompile(`^Day(\d+)$`)
+	lessonDir = regexp.MustCompile(`^(\d+)_(.+)$`)
+)
+
+// Index returns every lesson under root/Codes, ordered by day, lesson
+// number and directory.
+func Index(root string) ([]*Lesson, error) {
+	days, err := os.ReadDir(filepath.Join(root, "Codes"))
+	if err != nil {
+		return nil, err
+	}
+	var all []*Lesson
+	for _, d := range days {
+		m := dayDir.FindStringSubmatch(d.Name())
+		if !d.IsDir() || m == nil {
+			continue
+		}
+		day, _ := strconv.Atoi(m[1])
+		rel := filepath.Join("Codes", d.Name())
+		entries, err := os.ReadDir(filepath.Join(root, rel))
+		if err != nil {
+			return nil, err
+		}
+
+		var loose []string // files directly under the day directory
+		for _, e := range entries {
+			switch {
+			case e.IsDir():
+				l, err := load(root, filepath.Join(rel, e.Name()))
+				if err != nil {
+					return nil, err
+				}
+				if l == nil {
+					continue
+				}
+				l.Day = day
+				l.Name = e.Name()
+				if m := lessonDir.FindStringSubmatch(e.Name()); m != nil {
+					l.Order, _ = strconv.Atoi(m[1])
+					l.ID = fmt.Sprintf("day%d/%s", day, slug(m[2]))
+				} else {
+					l.ID = fmt.Sprintf("day%d/%s", day, slug(e.Name()))
+				}
+				all = append(all, l)
+			case isGoSource(e.Name()):
+				loose = append(loose, e.Name())
+			}
+		}
+		if len(loose) > 0 {
+			l, err := load(root, rel)
+			if err != nil {
+				return nil, err
+			}
+			l.Day = day
+			l.Name = strings.TrimSuffix(loose[0], ".go")
+			l.ID = fmt.Sprintf("day%d/%s", day, slug(l.Name))
+			all = append(all, l)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Day != all[j].Day {
+			return all[i].Day < all[j].Day
+		}
+		if all[i].Order != all[j].Order {
+			return all[i].Order < all[j].Order
+		}
+		return all[i].Dir < all[j].Dir // unnumbered lessons of a day
+	})
+	return all, nil
+}
+
+// load reads the Go files in dir. It returns nil for a directory without
+// any, such as one holding only a compiled binary.
+func load(root, dir string) (*Lesson, error) {
+	entries, err := os.ReadDir(filepath.Join(root, dir))
+	if err != nil {
+		return nil, err
+	}
+	l := &Lesson{Dir: dir}
+	for _, e := range entries {
+		if !e.IsDir() && isGoSource(e.Name()) {
+			l.Files = append(l.Files, e.Name())
+		}
+	}
+	if len(l.Files) == 0 {
+		return nil, nil
+	}
+	l.Topic, err = topic(filepath.Join(root, dir, l.Files[0]))
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func isGoSource(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")
+}
+
+// slug lowercases name and drops everything but letters and digits, so
+// "Operator_precedence" becomes "operatorprecedence".
+func slug(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// topic finds a lesson's headline: a "Topic :" line if the file has one,
+// otherwise the first title-like comment line or string literal printed by
+// main, whichever comes first in the file.
+func topic(path string) (string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+
+	type candidate struct {
+		pos  token.Pos
+		text string
+	}
+	var cands []candidate
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			text := strings.TrimPrefix(strings.TrimPrefix(c.Text, "//"), "/*")
+			text = strings.TrimSuffix(text, "*/")
+			for _, line := range strings.Split(text, "\n") {
+				if t, ok := strings.CutPrefix(strings.TrimSpace(line), "Topic :"); ok {
+					return strings.TrimSpace(t), nil
+				}
+				cands = append(cands, candidate{c.Pos(), line})
+			}
+		}
+	}
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "main" || fn.Body == nil {
+			continue
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if s, err := strconv.Unquote(lit.Value); err == nil {
+					cands = append(cands, candidate{lit.Pos(), s})
+				}
+			}
+			return true
+		})
+	}
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].pos < cands[j].pos })
+	for _, c := range cands {
+		if t := headline(c.text); t != "" {
+			return t, nil
+		}
+	}
+	return "", nil
+}
+
+// headline strips banner decoration from s and reports whether what is
+// left reads like a title.
+func headline(s string) string {
+	s = strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("=-*/#:", r)
+	})
+	letters := 0
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	if letters < 3 {
+		return ""
+	}
+	return s
+}
+
+// ErrNotFound is returned by Find when no lesson matches.
+var ErrNotFound = errors.New("no such lesson")
+
+// Find resolves a query such as "day7/bitwise", "day7/04", "day7/4",
+// "loops" or "day8" to exactly one lesson. Names match by prefix or
+// substring of the lesson's ID. A number matches the lessons with that
+// order, so "04" without a day is ambiguous when several days have one.
+func Find(all []*Lesson, query string) (*Lesson, error) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	day, name, hasDay := strings.Cut(q, "/")
+	if !hasDay {
+		if strings.HasPrefix(q, "day") {
+			day, name = q, ""
+		} else {
+			day, name = "", q
+		}
+	}
+	name = slug(name)
+
+	var matches, numbered []*Lesson
+	for _, l := range all {
+		if day != "" && day != fmt.Sprintf("day%d", l.Day) {
+			continue
+		}
+		_, lname, _ := strings.Cut(l.ID, "/")
+		switch {
+		case name == "":
+			matches = append(matches, l)
+		case lname == name:
+			return l, nil
+		case l.Order > 0 && isNumber(name):
+			if n, _ := strconv.Atoi(name); n == l.Order {
+				numbered = append(numbered, l)
+			}
+		case strings.Contains(lname, name):
+			matches = append(matches, l)
+		}
+	}
+	if len(numbered) > 0 {
+		matches = numbered
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, query)
+	case 1:
+		return matches[0], nil
+	}
+	ids := make([]string, len(matches))
+	for i, l := range matches {
+		ids[i] = l.ID
+	}
+	return nil, fmt.Errorf("lesson %q is ambiguous: %s", query, strings.Join(ids, ", "))
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+// Source returns the lesson's Go source. Multiple files are concatenated
+// in directory order.
+func (l *Lesson) Source(root string) ([]byte, error) {
+	var src []byte
+	for _, name := range l.Files {
+		b, err := os.ReadFile(filepath.Join(root, l.Dir, name))
+		if err != nil {
+			return nil, err
+		}
+		src = append(src, b...)
+	}
+	return src, nil
+}
+
+// Command returns a `go run` command for the lesson, to be run from the
+// repository root.
+func (l *Lesson) Command(ctx context.Context, root string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "go", append([]string{"run", "./" + filepath.ToSlash(l.Dir)}, args...)...)
+	cmd.Dir = root
+	return cmd
+}
+
+// Run runs the lesson, writing its output to stdout and stderr.
+func (l *Lesson) Run(ctx context.Context, root string, stdout, stderr io.Writer) error {
+	cmd := l.Command(ctx, root)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// Root walks up from dir to the repository root, the first directory
+// that contains both go.mod and Codes/.
+func Root(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if isFile(filepath.Join(dir, "go.mod")) && isDir(filepath.Join(dir, "Codes")) {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("not inside the GoTrainings repository (no go.mod next to Codes/)")
+		}
+		dir = parent
+	}
+}
+
+// Target turns a command-line argument into a directory to run the go
+// command from and a package or file to hand it. arg is either an existing
+// path, used relative to the working directory, or a lesson query as
+// understood by Find, resolved from the repository root.
+func Target(arg string) (dir, target string, err error) {
+	if _, err := os.Stat(arg); err == nil {
+		if !filepath.IsAbs(arg) && filepath.Ext(arg) != ".go" {
+			arg = "./" + filepath.Clean(arg)
+		}
+		return ".", arg, nil
+	}
+	root, err := Root(".")
+	if err != nil {
+		return "", "", err
+	}
+	all, err := Index(root)
+	if err != nil {
+		return "", "", err
+	}
+	l, err := Find(all, arg)
+	if err != nil {
+		return "", "", err
+	}
+	return root, "./" + filepath.ToSlash(l.Dir), nil
+}
+
+func isFile(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && !fi.IsDir()
+}
+
+func isDir(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
@@ -0,0 +1,278 @@
+package lessons
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func index(t *testing.T) (string, []*Lesson) {
+	t.Helper()
+	root, err := Root(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := Index(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root, all
+}
+
+// TestIndex pins every lesson, in order, with the topic the heuristic
+// finds for it. A new lesson, or an edit to a lesson's first lines, shows
+// up here.
+func TestIndex(t *testing.T) {
+	_, all := index(t)
+	want := []struct{ id, dir, topic string }{
+		{"day3/variables", "Codes/Day3", "Variables in Golang"},
+		{"day4/naming", "Codes/Day4/01_naming", "Go Naming Convention"},
+		{"day4/datatypes", "Codes/Day4/02_dataTypes", "Go data types are broadly categorized as"},
+		{"day4/intsizevariations", "Codes/Day4/03_intsizevariations", "INTEGER TYPES"},
+		{"day4/minmax", "Codes/Day4/04_minmax", "MIN/MAX VALUES"},
+		{"day4/zerovalues", "Codes/Day4/05_ZeroValues", "ZERO VALUES IN GO"},
+		{"day4/cheatsheet", "Codes/Day4/06_CheatSheet", "Data Type Summary"},
+		{"day4/escapeanalysis", "Codes/Day4/07_EscapeAnalysis", "ESCAPE ANALYSIS IN GO - SIMPLE GUIDE"},
+		{"day5/basic", "Codes/Day5/01_basic", "Basic Conditionals in Go"},
+		{"day6/switch", "Codes/Day6/02_Switch", "SWITCH STATEMENT FUNDAMENTALS"},
+		{"day7/arithmeticoperators", "Codes/Day7/01_ArithmeticOperators", "Arithmetic Operators in Go"},
+		{"day7/comparisionoperators", "Codes/Day7/02_ComparisionOperators", "Comparison Operators in Go"},
+		{"day7/assignmentoperators", "Codes/Day7/03_AssignmentOperators", "Assignment Operators in Go"},
+		{"day7/bitwiseoperators", "Codes/Day7/04_BitwiseOperators", "BITWISE OPERATORS IN GO"},
+		// The lesson's own banner, copied from the one before it.
+		{"day7/logicaloperators", "Codes/Day7/05_LogicalOperators", "BITWISE OPERATORS IN GO"},
+		{"day7/operatorprecedence", "Codes/Day7/06_Operator_precedence", "OPERATOR PRECEDENCE"},
+		{"day8/loops", "Codes/Day8", "GO FOR LOOP COMPLETE GUIDE"},
+		{"day9/functionbasics", "Codes/Day9/01_function_basics", "FUNCTIONS IN GO"},
+		{"day9/returntype", "Codes/Day9/02_return_type", "RETURN CONCEPTS IN GO"},
+		{"day9/callstack", "Codes/Day9/03_Call_stack", "DEMONSTRATING FUNCTION CALL ORDER AND CALL STACK"},
+		{"day9/recursion", "Codes/Day9/04_recursion", "RECURSION: A FUNCTION THAT CALLS ITSELF"},
+		{"day9/variadicfunctions", "Codes/Day9/05_variadic_functions", "VARIADIC FUNCTIONS"},
+		{"day9/anonymousfunctions", "Codes/Day9/06_anonymous_functions", "ANONYMOUS FUNCTIONS (FUNCTIONS WITHOUT A NAME)"},
+	}
+	if len(all) != len(want) {
+		var ids []string
+		for _, l := range all {
+			ids = append(ids, l.ID)
+		}
+		t.Fatalf("Index found %d lessons, want %d:\n%s", len(all), len(want), strings.Join(ids, "\n"))
+	}
+	for i, w := range want {
+		l := all[i]
+		if l.ID != w.id || filepath.ToSlash(l.Dir) != w.dir || l.Topic != w.topic {
+			t.Errorf("lesson %d = %s %s %q, want %s %s %q", i, l.ID, l.Dir, l.Topic, w.id, w.dir, w.topic)
+		}
+		if len(l.Files) == 0 {
+			t.Errorf("%s: no Go files", l.ID)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	_, all := index(t)
+	tests := []struct {
+		query, want string
+	}{
+		{"day7/bitwise", "day7/bitwiseoperators"},
+		{"day7/04", "day7/bitwiseoperators"},
+		{"day7/4", "day7/bitwiseoperators"},
+		{"DAY7/Bitwise", "day7/bitwiseoperators"},
+		{"day7/Operator_precedence", "day7/operatorprecedence"},
+		{"loops", "day8/loops"},
+		{"day8", "day8/loops"},
+		{" day3 ", "day3/variables"},
+		{"recursion", "day9/recursion"},
+		{"escape", "day4/escapeanalysis"},
+	}
+	for _, tt := range tests {
+		l, err := Find(all, tt.query)
+		if err != nil {
+			t.Errorf("Find(%q): %v", tt.query, err)
+			continue
+		}
+		if l.ID != tt.want {
+			t.Errorf("Find(%q) = %s, want %s", tt.query, l.ID, tt.want)
+		}
+	}
+
+	for _, query := range []string{"xyz", "day2", "day7/99", "day8/bitwise"} {
+		if _, err := Find(all, query); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Find(%q) = %v, want ErrNotFound", query, err)
+		}
+	}
+
+	ambiguous := []struct {
+		query, err string
+	}{
+		{"day7", `lesson "day7" is ambiguous: day7/arithmeticoperators, day7/comparisionoperators, day7/assignmentoperators, day7/bitwiseoperators, day7/logicaloperators, day7/operatorprecedence`},
+		{"day9/functions", `lesson "day9/functions" is ambiguous: day9/variadicfunctions, day9/anonymousfunctions`},
+		{"04", `lesson "04" is ambiguous: day4/minmax, day7/bitwiseoperators, day9/recursion`},
+		{"6", `lesson "6" is ambiguous: day4/cheatsheet, day7/operatorprecedence, day9/anonymousfunctions`},
+	}
+	for _, tt := range ambiguous {
+		if _, err := Find(all, tt.query); err == nil || err.Error() != tt.err {
+			t.Errorf("Find(%q) = %v, want %s", tt.query, err, tt.err)
+		}
+	}
+}
+
+// An exact name wins over the lessons it is a substring of.
+func TestFindExact(t *testing.T) {
+	all := []*Lesson{
+		{Day: 1, Order: 1, ID: "day1/loopsadvanced"},
+		{Day: 1, Order: 2, ID: "day1/loops"},
+		{Day: 2, Order: 1, ID: "day2/loops"},
+	}
+	if l, err := Find(all, "day1/loops"); err != nil || l != all[1] {
+		t.Errorf("Find(day1/loops) = %v, %v; want day1/loops", l, err)
+	}
+	if l, err := Find(all, "day1/2"); err != nil || l != all[1] {
+		t.Errorf("Find(day1/2) = %v, %v; want day1/loops", l, err)
+	}
+	if _, err := Find(all, "loop"); err == nil {
+		t.Error("Find(loop) matched one lesson, want it ambiguous")
+	}
+	if l, err := Find(all, "2"); err != nil || l != all[1] {
+		t.Errorf("Find(2) = %v, %v; want day1/loops, the only lesson 2", l, err)
+	}
+	if _, err := Find(all, "1"); err == nil {
+		t.Error("Find(1) matched one lesson, want lesson 1 of both days")
+	}
+}
+
+// Unnumbered lessons of a day are ordered by directory, whatever order
+// the file system lists them in.
+func TestIndexOrder(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"Codes/Day1/zeta", "Codes/Day1/alpha", "Codes/Day1/02_second", "Codes/Day1", "Codes/Day1/01_first"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, err := Index(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, l := range all {
+		ids = append(ids, l.ID)
+	}
+	want := "day1/main day1/alpha day1/zeta day1/first day1/second"
+	if got := strings.Join(ids, " "); got != want {
+		t.Errorf("Index = %s, want %s", got, want)
+	}
+}
+
+func TestHeadline(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"=== ZERO VALUES IN GO ===", "ZERO VALUES IN GO"},
+		{"\n--- Topic ---\n", "Topic"},
+		{"// Variables in Golang:", "Variables in Golang"},
+		{"# MIN/MAX VALUES", "MIN/MAX VALUES"},
+		{"=======", ""},
+		{"1. a", ""},
+		{"x := 5", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := headline(tt.in); got != tt.want {
+			t.Errorf("headline(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTopic(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{
+			"topic line wins",
+			"package main\n\nimport \"fmt\"\n\n// ====\n// Shown first\n\n/*\nTopic : The real topic\n*/\n\nfunc main() { fmt.Println(\"Banner\") }\n",
+			"The real topic",
+		},
+		{
+			"first comment",
+			"package main\n\n// ====== LOOPS ======\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"=== Banner ===\") }\n",
+			"LOOPS",
+		},
+		{
+			"string printed before any comment",
+			"package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"=== BANNER ===\")\n\t// later comment\n}\n",
+			"BANNER",
+		},
+		{
+			"short lines skipped",
+			"package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"---\")\n\tfmt.Println(\"1.\")\n\tfmt.Println(\"Functions\")\n}\n",
+			"Functions",
+		},
+		{
+			"strings outside main ignored",
+			"package main\n\nconst title = \"Constant title\"\n\nfunc main() {}\n",
+			"",
+		},
+	}
+	dir := t.TempDir()
+	for _, tt := range tests {
+		path := filepath.Join(dir, "main.go")
+		if err := os.WriteFile(path, []byte(tt.src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := topic(path)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: topic = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRoot(t *testing.T) {
+	root, err := Root(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != want {
+		t.Errorf("Root(.) = %s, want %s", root, want)
+	}
+	if _, err := Root(t.TempDir()); err == nil {
+		t.Error("Root outside the repository succeeded")
+	}
+}
+
+func TestTarget(t *testing.T) {
+	root, _ := index(t)
+	tests := []struct {
+		arg, dir, target string
+	}{
+		{"day7/04", root, "./Codes/Day7/04_BitwiseOperators"},
+		{"loops", root, "./Codes/Day8"},
+		{"lessons.go", ".", "lessons.go"},
+		{"testdata", ".", "./testdata"},
+	}
+	for _, tt := range tests {
+		dir, target, err := Target(tt.arg)
+		if err != nil {
+			t.Errorf("Target(%q): %v", tt.arg, err)
+			continue
+		}
+		if dir != tt.dir || target != tt.target {
+			t.Errorf("Target(%q) = %s, %s; want %s, %s", tt.arg, dir, target, tt.dir, tt.target)
+		}
+	}
+	if _, _, err := Target("xyz"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Target(xyz) = %v, want ErrNotFound", err)
+	}
+}
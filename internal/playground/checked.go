@@ -0,0 +1,278 @@
+package playground
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// checked is a type-checked program.
+type checked struct {
+	prog *program
+	fset *token.FileSet
+	file *ast.File
+	pkg  *types.Package
+	info *types.Info
+
+	unused map[int]bool // lines of imports nothing uses
+}
+
+// inInput reports whether n lies in the input's lines.
+func (c *checked) inInput(n ast.Node) bool {
+	line := c.fset.Position(n.Pos()).Line
+	return line >= c.prog.inputLine && line <= c.prog.inputEnd
+}
+
+func (c *checked) typeString(t types.Type) string {
+	return types.TypeString(t, types.RelativeTo(c.pkg))
+}
+
+// inMain reports whether pos is that of a variable declared directly in
+// main, which a later input may still use.
+func (c *checked) inMain(pos token.Pos) bool {
+	main := c.mainFunc()
+	if main == nil {
+		return false
+	}
+	scope := c.info.Scopes[main.Type]
+	for id, obj := range c.info.Defs {
+		if id.Pos() == pos && obj != nil {
+			return obj.Parent() == scope
+		}
+	}
+	return false
+}
+
+// mainFunc returns the generated main function.
+func (c *checked) mainFunc() *ast.FuncDecl {
+	for _, d := range c.file.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Name.Name == "main" && fn.Recv == nil {
+			return fn
+		}
+	}
+	return nil
+}
+
+// bindings lists what the input declared or assigned, in source order.
+func (c *checked) bindings() []Binding {
+	var out []Binding
+	add := func(e ast.Expr) {
+		id := root(e)
+		if id == nil || id.Name == "_" {
+			return
+		}
+		if _, ok := c.info.Uses[id].(*types.PkgName); ok {
+			return
+		}
+		if obj := c.info.Defs[id]; obj != nil {
+			out = append(out, c.binding(obj, true))
+		} else if obj := c.info.Uses[id]; obj != nil {
+			out = append(out, c.binding(obj, false))
+		}
+	}
+
+	switch c.prog.kind {
+	case declInput:
+		for _, d := range c.file.Decls {
+			if !c.inInput(d) {
+				continue
+			}
+			switch d := d.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					add(d.Name)
+				} else if fn, ok := c.info.Defs[d.Name].(*types.Func); ok {
+					recv := fn.Signature().Recv().Type()
+					out = append(out, Binding{Name: "(" + c.typeString(recv) + ")." + fn.Name(), Kind: "func",
+						Type: strings.TrimPrefix(c.typeString(fn.Type()), "func"), New: true})
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						add(ts.Name)
+					}
+				}
+			}
+		}
+		return out
+	case importInput:
+		return nil
+	}
+
+	main := c.mainFunc()
+	if main == nil {
+		return nil
+	}
+	for _, st := range main.Body.List {
+		if !c.inInput(st) {
+			continue
+		}
+		switch st := st.(type) {
+		case *ast.AssignStmt:
+			if c.prog.kind == exprInput {
+				// The result variable stands for the expression.
+				tv := c.info.Types[st.Rhs[0]]
+				out = append(out, c.values(tv)...)
+				continue
+			}
+			for _, lhs := range st.Lhs {
+				add(lhs)
+			}
+		case *ast.IncDecStmt:
+			add(st.X)
+		case *ast.DeclStmt:
+			for _, spec := range st.Decl.(*ast.GenDecl).Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					for _, id := range spec.Names {
+						add(id)
+					}
+				case *ast.TypeSpec:
+					add(spec.Name)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// root returns the variable an assignment's target belongs to: x for x,
+// p.X, s[i] or *p.
+func root(e ast.Expr) *ast.Ident {
+	for {
+		switch x := ast.Unparen(e).(type) {
+		case *ast.Ident:
+			return x
+		case *ast.SelectorExpr:
+			e = x.X
+		case *ast.IndexExpr:
+			e = x.X
+		case *ast.StarExpr:
+			e = x.X
+		default:
+			return nil
+		}
+	}
+}
+
+// values describes the results of an expression input.
+func (c *checked) values(tv types.TypeAndValue) []Binding {
+	if tv.Type == nil {
+		return nil
+	}
+	if tuple, ok := tv.Type.(*types.Tuple); ok {
+		var out []Binding
+		for i := range tuple.Len() {
+			out = append(out, Binding{Kind: "value", Type: c.typeString(tuple.At(i).Type())})
+		}
+		return out
+	}
+	b := Binding{Kind: "value", Type: c.typeString(tv.Type)}
+	if tv.Value != nil {
+		b.Kind, b.Value = "const", tv.Value.ExactString()
+		if basic, ok := tv.Type.(*types.Basic); ok && basic.Info()&types.IsUntyped != 0 {
+			b.Type = basic.String()
+		}
+	}
+	return []Binding{b}
+}
+
+// constant reports whether the expression input has a constant value,
+// which needs no run to print.
+func (c *checked) constant() bool {
+	bs := c.bindings()
+	return len(bs) == 1 && bs[0].Kind == "const"
+}
+
+func (c *checked) binding(obj types.Object, isNew bool) Binding {
+	b := Binding{Name: obj.Name(), Type: c.typeString(obj.Type()), New: isNew}
+	switch obj := obj.(type) {
+	case *types.Var:
+		b.Kind = "var"
+	case *types.Const:
+		b.Kind, b.Value = "const", obj.Val().ExactString()
+	case *types.TypeName:
+		b.Kind, b.Type = "type", c.typeString(obj.Type().Underlying())
+	case *types.Func:
+		b.Kind, b.Type = "func", strings.TrimPrefix(b.Type, "func")
+	}
+	return b
+}
+
+// scope lists the package-level functions and types, then the names
+// declared directly in main, in declaration order.
+func (c *checked) scope() []Binding {
+	var out []Binding
+	var objs []types.Object
+	for _, name := range c.pkg.Scope().Names() {
+		if obj := c.pkg.Scope().Lookup(name); name != "main" {
+			objs = append(objs, obj)
+		}
+	}
+	if main := c.mainFunc(); main != nil {
+		s := c.info.Scopes[main.Type]
+		for _, name := range s.Names() {
+			if !strings.HasPrefix(name, resultVars) {
+				objs = append(objs, s.Lookup(name))
+			}
+		}
+	}
+	sort.SliceStable(objs, func(i, j int) bool { return objs[i].Pos() < objs[j].Pos() })
+	for _, obj := range objs {
+		out = append(out, c.binding(obj, false))
+	}
+	return out
+}
+
+// withPrints returns the program's source with a print of every value
+// the input produced: the variables it declared or assigned, or the
+// results of an expression.
+func (c *checked) withPrints() string {
+	var names []string
+	src := c.prog.src
+	if c.prog.kind == exprInput {
+		for i := range c.bindings() {
+			names = append(names, fmt.Sprintf("%s%d", resultVars, i))
+		}
+		src = strings.Replace(src, resultVars+" := ", strings.Join(names, ", ")+" := ", 1)
+	} else {
+		for _, b := range c.bindings() {
+			if b.Kind == "var" {
+				names = append(names, b.Name)
+			}
+		}
+	}
+	return c.printing(src, names)
+}
+
+// printing returns src, the program's source, ready to run: without the
+// unused imports, with every variable of main used, and printing names at
+// the end of main, each followed by a NUL byte, after the marker that
+// separates them from the input's output.
+func (c *checked) printing(src string, names []string) string {
+	var b strings.Builder
+	lines := strings.SplitAfter(src, "\n")
+	for i, line := range lines {
+		if c.unused[i+1] && !strings.Contains(line, fmtName) {
+			continue
+		}
+		if i == len(lines)-2 { // the closing brace of main
+			if main := c.mainFunc(); main != nil {
+				for _, name := range c.info.Scopes[main.Type].Names() {
+					if _, ok := c.info.Scopes[main.Type].Lookup(name).(*types.Var); ok && !strings.HasPrefix(name, resultVars) {
+						fmt.Fprintf(&b, "_ = %s\n", name)
+					}
+				}
+			}
+			b.WriteString(marker)
+			for _, name := range names {
+				fmt.Fprintf(&b, "%s.Printf(\"%%#v\\x00\", %s)\n", fmtName, name)
+			}
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
@@ -0,0 +1,339 @@
+// Package playground is the engine of goplay, a line-by-line Go prompt for
+// practising declarations. A Session keeps everything entered so far as
+// the body of a main function, plus imports and package-level func and
+// type declarations; each new input is type-checked with go/types against
+// that history before it is accepted.
+//
+// Values are not interpreted: the accepted program is run with go run and
+// prints the variables the input declared or assigned. An expression is
+// shown but not kept, so its side effects do not carry over. Earlier
+// inputs run again each time with their output hidden, and inputs whose
+// result depends on the clock or on randomness may show different values
+// later.
+package playground
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Binding is a name the input declared or assigned, or for an expression
+// input, one of its values (with an empty Name).
+type Binding struct {
+	Name  string `json:"name,omitempty"`
+	Kind  string `json:"kind"` // var, const, type, func or value
+	Type  string `json:"type"`
+	Value string `json:"value,omitempty"` // Go syntax, as printed by %#v
+	New   bool   `json:"new"`             // declared by this input rather than assigned
+}
+
+// Result is the outcome of one input.
+type Result struct {
+	Bindings []Binding `json:"bindings,omitempty"`
+	Output   string    `json:"output,omitempty"` // what the input printed
+	Errors   []string  `json:"errors,omitempty"` // compile or run-time errors; the input was not kept
+}
+
+// Session is the state of one prompt.
+type Session struct {
+	// Go is the go command used to run programs; "go" by default.
+	Go string
+
+	imports []string // import specs: `"strings"`, `str "strings"`
+	decls   []string // package-level func and type declarations
+	stmts   []string // statements of main
+	dir     string   // scratch directory for go run
+	fset    *token.FileSet
+	imp     types.Importer
+}
+
+// New returns an empty session. Close removes its scratch directory.
+func New() *Session {
+	fset := token.NewFileSet()
+	return &Session{Go: "go", fset: fset, imp: importer.ForCompiler(fset, "gc", nil)}
+}
+
+// Reset forgets every input.
+func (s *Session) Reset() {
+	s.imports, s.decls, s.stmts = nil, nil, nil
+}
+
+// Close removes the session's scratch files.
+func (s *Session) Close() error {
+	if s.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(s.dir)
+}
+
+// Complete reports whether src is a complete input, or whether the prompt
+// should read more lines because a bracket, brace or parenthesis, a raw
+// string or a comment is still open.
+func Complete(src string) bool {
+	var sc scanner.Scanner
+	file := token.NewFileSet().AddFile("", -1, len(src))
+	depth := 0
+	open := false
+	sc.Init(file, []byte(src), func(_ token.Position, msg string) {
+		if strings.Contains(msg, "not terminated") {
+			open = true
+		}
+	}, 0)
+	for {
+		_, tok, _ := sc.Scan()
+		switch tok {
+		case token.LPAREN, token.LBRACE, token.LBRACK:
+			depth++
+		case token.RPAREN, token.RBRACE, token.RBRACK:
+			depth--
+		case token.EOF:
+			return depth <= 0 && !open
+		}
+	}
+}
+
+// kind of input.
+type kind int
+
+const (
+	noInput     kind = iota // the history alone
+	importInput             // import declaration
+	declInput               // func or type declaration
+	stmtInput               // statements, kept in main
+	exprInput               // an expression, shown but not kept
+)
+
+// Eval type-checks and runs one input. The returned error is for failures
+// of the tool itself; mistakes in the input are reported in Result.Errors.
+func (s *Session) Eval(ctx context.Context, input string) (*Result, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return &Result{}, nil
+	}
+	next := *s
+	k := classify(input)
+	switch k {
+	case importInput:
+		specs, err := importSpecs(input)
+		if err != nil {
+			return &Result{Errors: []string{err.Error()}}, nil
+		}
+		next.imports = append(clone(s.imports), specs...)
+	case declInput:
+		next.decls = append(clone(s.decls), input)
+	default:
+		if isExpr(input) {
+			k = exprInput
+		} else {
+			next.stmts = append(clone(s.stmts), input)
+		}
+	}
+
+	c, errs := s.check(next.program(k, input))
+	if k == exprInput && len(errs) > 0 {
+		// A call without results, such as fmt.Println("hi"), has no value
+		// to show; it is a statement.
+		stmt := next
+		stmt.stmts = append(clone(s.stmts), input)
+		if sc, serrs := s.check(stmt.program(stmtInput, input)); len(serrs) == 0 {
+			next, k, c, errs = stmt, stmtInput, sc, nil
+		}
+	}
+	if len(errs) > 0 {
+		return &Result{Errors: errs}, nil
+	}
+
+	res := &Result{Bindings: c.bindings()}
+	if k == stmtInput || k == exprInput && !c.constant() {
+		out, values, runErr, err := s.run(ctx, c.withPrints())
+		if err != nil {
+			return nil, err
+		}
+		if runErr != "" {
+			return &Result{Output: out, Errors: []string{runErr}}, nil
+		}
+		res.Output = out
+		fill(res.Bindings, values, "var", "value")
+	}
+
+	s.imports, s.decls, s.stmts = next.imports, next.decls, next.stmts
+	return res, nil
+}
+
+// Scope returns everything declared so far, in declaration order:
+// package-level functions and types, and the variables and constants of
+// main with the current values of the variables.
+func (s *Session) Scope(ctx context.Context) ([]Binding, error) {
+	c, errs := s.check(s.program(noInput, ""))
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "\n"))
+	}
+	all := c.scope()
+	var vars []string
+	for _, b := range all {
+		if b.Kind == "var" {
+			vars = append(vars, b.Name)
+		}
+	}
+	if len(vars) == 0 {
+		return all, nil
+	}
+	_, values, runErr, err := s.run(ctx, c.printing(c.prog.src, vars))
+	if err != nil {
+		return nil, err
+	}
+	if runErr != "" {
+		return nil, errors.New(runErr)
+	}
+	fill(all, values, "var")
+	return all, nil
+}
+
+// Source returns the program the session has accepted so far, formatted
+// when it can be.
+func (s *Session) Source() string {
+	src := s.program(noInput, "").src
+	src = strings.Replace(src, "\t"+fmtName+" \"fmt\"\n", "", 1)
+	src = strings.ReplaceAll(src, marker, "")
+	if out, err := format.Source([]byte(src)); err == nil {
+		return string(out)
+	}
+	return src
+}
+
+// fill sets the values of the bindings of the given kinds, in order.
+func fill(bindings []Binding, values []string, kinds ...string) {
+	i := 0
+	for j := range bindings {
+		for _, k := range kinds {
+			if bindings[j].Kind == k && i < len(values) {
+				bindings[j].Value = values[i]
+				i++
+			}
+		}
+	}
+}
+
+func clone(list []string) []string {
+	return append([]string(nil), list...)
+}
+
+func classify(input string) kind {
+	first := strings.Fields(input)[0]
+	switch {
+	case first == "import" || strings.HasPrefix(first, "import("):
+		return importInput
+	case first == "type" || strings.HasPrefix(first, "type("):
+		return declInput
+	case first == "func" && !strings.HasPrefix(strings.TrimSpace(input[len("func"):]), "("):
+		return declInput // a named function
+	case first == "func" && isMethod(input):
+		return declInput
+	}
+	return stmtInput
+}
+
+// isMethod reports whether input, which starts with "func (", declares a
+// method rather than starting a function literal.
+func isMethod(input string) bool {
+	f, err := parser.ParseFile(token.NewFileSet(), "", "package p\n"+input, 0)
+	return err == nil && len(f.Decls) > 0
+}
+
+func isExpr(input string) bool {
+	_, err := parser.ParseExpr(input)
+	return err == nil
+}
+
+func importSpecs(input string) ([]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", "package p\n"+input, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+	var specs []string
+	for _, imp := range f.Imports {
+		spec := imp.Path.Value
+		if imp.Name != nil {
+			spec = imp.Name.Name + " " + spec
+		}
+		specs = append(specs, spec)
+	}
+	if len(specs) == 0 {
+		return nil, errors.New("no import path")
+	}
+	return specs, nil
+}
+
+// run writes src to the scratch directory and runs it. It returns what
+// the input printed and the values printed after it, or the run-time
+// error: a panic, or a build failure the type check did not predict.
+func (s *Session) run(ctx context.Context, src string) (output string, values []string, runErr string, err error) {
+	if s.dir == "" {
+		if s.dir, err = os.MkdirTemp("", "goplay"); err != nil {
+			return "", nil, "", err
+		}
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, "main.go"), []byte(src), 0o644); err != nil {
+		return "", nil, "", err
+	}
+	cmd := exec.CommandContext(ctx, s.Go, "run", "main.go")
+	cmd.Dir = s.dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err = cmd.Run()
+
+	// Output is: earlier inputs' output, NUL, the input's output, NUL,
+	// then each value followed by NUL.
+	parts := strings.Split(stdout.String(), "\x00")
+	if len(parts) > 1 {
+		output = parts[1]
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return output, nil, "timed out", nil
+	}
+	if ctx.Err() != nil {
+		return output, nil, ctx.Err().Error(), nil
+	}
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return output, nil, runError(stderr.String()), nil
+	}
+	if err != nil {
+		return "", nil, "", err
+	}
+	if len(parts) > 2 {
+		values = parts[2 : len(parts)-1]
+	}
+	for i, v := range values {
+		values[i] = mainQualifier.ReplaceAllString(v, "$1")
+	}
+	return output, values, "", nil
+}
+
+// mainQualifier matches the package name %#v puts before the types
+// declared at the prompt, as in main.Point{X:1, Y:2}.
+var mainQualifier = regexp.MustCompile(`\bmain\.(\w+[{(])`)
+
+// runError shortens go run's standard error to the message a student
+// needs: the panic without the goroutine dump, or the build errors.
+func runError(stderr string) string {
+	msg := strings.TrimPrefix(stderr, "# command-line-arguments\n")
+	if i := strings.Index(msg, "\n\ngoroutine "); i >= 0 {
+		msg = msg[:i]
+	}
+	msg = strings.TrimSuffix(strings.TrimSpace(msg), "exit status 2")
+	return strings.TrimSpace(msg)
+}
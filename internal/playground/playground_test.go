@@ -0,0 +1,144 @@
+package playground
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"x := 5", true},
+		{"", true},
+		{"func add(a, b int) int {", false},
+		{"func add(a, b int) int {\n\treturn a + b\n}", true},
+		{"s := []int{1, 2,", false},
+		{"f(a[0],", false},
+		{"m := map[string]int{\"a\": 1}", true},
+		{"s := `first line", false},
+		{"s := `first line\nsecond`", true},
+		{"x := 1 /* still", false},
+		{"x := 1 // a comment", true},
+		{"x := \"}\"", true},
+		{"}", true}, // too many closers is an error for the type checker, not more input
+	}
+	for _, tt := range tests {
+		if got := Complete(tt.src); got != tt.want {
+			t.Errorf("Complete(%q) = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		input string
+		want  kind
+	}{
+		{`import "strings"`, importInput},
+		{"import (\n\t\"os\"\n)", importInput},
+		{`import("os")`, importInput},
+		{"type Point struct{ X, Y int }", declInput},
+		{"type(\n\tA int\n)", declInput},
+		{"func add(a, b int) int { return a + b }", declInput},
+		{"func (p Point) Norm() int { return p.X*p.X + p.Y*p.Y }", declInput},
+		{"func() { x++ }()", stmtInput},
+		{"func (a int) int { return a }(2)", stmtInput},
+		{"x := 5", stmtInput},
+		{"var x, y = 1, 2", stmtInput},
+		{"const Pi = 3.14", stmtInput},
+		{"x * 2", stmtInput}, // Eval tells expressions apart
+	}
+	for _, tt := range tests {
+		if got := classify(tt.input); got != tt.want {
+			t.Errorf("classify(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRunError(t *testing.T) {
+	tests := []struct {
+		name, stderr, want string
+	}{
+		{
+			"panic",
+			"panic: assignment to entry in nil map\n\ngoroutine 1 [running]:\nmain.main()\n\t/tmp/goplay1/main.go:9 +0x2e\nexit status 2\n",
+			"panic: assignment to entry in nil map",
+		},
+		{
+			"runtime error",
+			"panic: runtime error: index out of range [3] with length 2\n\ngoroutine 1 [running]:\nmain.main()\n\t/tmp/goplay1/main.go:7 +0x1d\nexit status 2\n",
+			"panic: runtime error: index out of range [3] with length 2",
+		},
+		{
+			"build error",
+			"# command-line-arguments\n./main.go:8:2: declared and not used: y\n./main.go:9:7: undefined: z\n",
+			"./main.go:8:2: declared and not used: y\n./main.go:9:7: undefined: z",
+		},
+		{"exit status only", "exit status 2\n", ""},
+	}
+	for _, tt := range tests {
+		if got := runError(tt.stderr); got != tt.want {
+			t.Errorf("%s: runError = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestEval runs programs with go run.
+func TestEval(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go run for every input")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	s := New()
+	defer s.Close()
+
+	steps := []struct {
+		input string
+		want  Result
+	}{
+		{"x := 5", Result{Bindings: []Binding{{Name: "x", Kind: "var", Type: "int", Value: "5", New: true}}}},
+		{"x := 6", Result{Errors: []string{"1:3: no new variables on left side of :="}}},
+		{"var s []int", Result{Bindings: []Binding{{Name: "s", Kind: "var", Type: "[]int", Value: "[]int(nil)", New: true}}}},
+		{"s = append(s, x)", Result{Bindings: []Binding{{Name: "s", Kind: "var", Type: "[]int", Value: "[]int{5}"}}}},
+		{"x * 2", Result{Bindings: []Binding{{Kind: "value", Type: "int", Value: "10"}}}},
+		{`import "fmt"`, Result{}},
+		{`fmt.Println("hi")`, Result{Output: "hi\n"}},
+		{"type P struct{ X, Y int }", Result{Bindings: []Binding{{Name: "P", Kind: "type", Type: "struct{X int; Y int}", New: true}}}},
+		{"p := P{1, 2}", Result{Bindings: []Binding{{Name: "p", Kind: "var", Type: "P", Value: "P{X:1, Y:2}", New: true}}}},
+		{"var m map[string]int", Result{Bindings: []Binding{{Name: "m", Kind: "var", Type: "map[string]int", Value: "map[string]int(nil)", New: true}}}},
+		{`m["a"] = 1`, Result{Errors: []string{"panic: assignment to entry in nil map"}}},
+	}
+	for _, step := range steps {
+		got, err := s.Eval(ctx, step.input)
+		if err != nil {
+			t.Fatalf("Eval(%q): %v", step.input, err)
+		}
+		if !reflect.DeepEqual(*got, step.want) {
+			t.Errorf("Eval(%q) = %+v, want %+v", step.input, *got, step.want)
+		}
+	}
+
+	// Rejected inputs are not kept, and earlier output is not repeated.
+	scope, err := s.Scope(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, b := range scope {
+		names = append(names, b.Name+"="+b.Value)
+	}
+	want := []string{"P=", "x=5", "s=[]int{5}", "p=P{X:1, Y:2}", "m=map[string]int(nil)"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Scope = %q, want %q", names, want)
+	}
+
+	s.Reset()
+	if got, _ := s.Eval(ctx, "x"); len(got.Errors) != 1 || got.Errors[0] != "1:1: undefined: x" {
+		t.Errorf("after Reset, Eval(x) = %+v, want undefined: x", got)
+	}
+}
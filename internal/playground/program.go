@@ -0,0 +1,160 @@
+package playground
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// program is a session's history plus one input, as Go source.
+type program struct {
+	src       string
+	kind      kind
+	inputLine int // first line of src holding the input; 0 for noInput
+	inputEnd  int // last line of src holding the input
+}
+
+// The generated program imports fmt under this name, which a student is
+// unlikely to use, to print values.
+const fmtName = "playfmt"
+
+// resultVars names the variables that receive an expression's values.
+// One is enough to type-check it; withPrints numbers them once the type
+// check tells how many values there are.
+const resultVars = "playResult"
+
+// marker separates the output of earlier inputs from that of the new one.
+const marker = fmtName + ".Print(\"\\x00\")\n"
+
+// program writes the session as a Go file, with input, which the session
+// already holds unless it is an expression, marked as the new input.
+func (s *Session) program(k kind, input string) *program {
+	var b strings.Builder
+	line := 1
+	write := func(text string) {
+		b.WriteString(text)
+		line += strings.Count(text, "\n")
+	}
+	p := &program{kind: k}
+	writeInput := func(text string) {
+		p.inputLine = line
+		write(text)
+		p.inputEnd = line
+		write("\n")
+	}
+
+	write("package main\n\nimport (\n\t" + fmtName + " \"fmt\"\n")
+	for i, imp := range s.imports {
+		if k == importInput && i == len(s.imports)-1 {
+			writeInput("\t" + imp)
+		} else {
+			write("\t" + imp + "\n")
+		}
+	}
+	write(")\n\n")
+	for i, d := range s.decls {
+		if k == declInput && i == len(s.decls)-1 {
+			writeInput(d)
+		} else {
+			write(d + "\n")
+		}
+		write("\n")
+	}
+	write("func main() {\n")
+	for i, st := range s.stmts {
+		if k == stmtInput && i == len(s.stmts)-1 {
+			write(marker)
+			writeInput(st)
+		} else {
+			write(st + "\n")
+		}
+	}
+	if k == exprInput {
+		write(marker)
+		writeInput(resultVars + " := " + input)
+	}
+	if k == noInput {
+		write(marker)
+	}
+	write("}\n")
+	p.src = b.String()
+	return p
+}
+
+// check type-checks p and returns its errors, with positions in the input
+// relative to the input. Unused variables of main and unused imports are
+// not errors at a prompt: they are normal until a later input uses them.
+func (s *Session) check(p *program) (*checked, []string) {
+	f, err := parser.ParseFile(s.fset, "main.go", p.src, parser.SkipObjectResolution)
+	if err != nil {
+		var list scanner.ErrorList
+		if errors.As(err, &list) {
+			var msgs []string
+			for _, e := range list {
+				msgs = append(msgs, p.relative(e.Pos, e.Msg))
+			}
+			return nil, msgs
+		}
+		return nil, []string{err.Error()}
+	}
+	c := &checked{
+		prog:   p,
+		fset:   s.fset,
+		file:   f,
+		unused: map[int]bool{},
+		info: &types.Info{
+			Types:  map[ast.Expr]types.TypeAndValue{},
+			Defs:   map[*ast.Ident]types.Object{},
+			Uses:   map[*ast.Ident]types.Object{},
+			Scopes: map[ast.Node]*types.Scope{},
+		},
+	}
+	var all []types.Error
+	conf := types.Config{
+		Importer: s.imp,
+		Error:    func(err error) { all = append(all, err.(types.Error)) },
+	}
+	c.pkg, _ = conf.Check("main", s.fset, []*ast.File{f}, c.info)
+
+	var msgs []string
+	for _, e := range all {
+		pos := s.fset.Position(e.Pos)
+		switch {
+		case strings.Contains(e.Msg, "imported") && strings.HasSuffix(e.Msg, "not used"):
+			c.unused[pos.Line] = true
+			continue
+		case strings.HasPrefix(e.Msg, "declared and not used") && c.inMain(e.Pos):
+			continue
+		}
+		msg := e.Msg
+		if p.kind == exprInput {
+			// Report the expression's error, not the assignment's.
+			msg = strings.Replace(msg, resultVars+" := ", "", 1)
+		}
+		msgs = append(msgs, p.relative(pos, msg))
+	}
+	return c, msgs
+}
+
+// relative rewrites a position in the generated program as line:col of
+// the input, or drops it when the error lies outside the input.
+func (p *program) relative(pos token.Position, msg string) string {
+	if p.inputLine == 0 || pos.Line < p.inputLine || pos.Line > p.inputEnd {
+		return msg
+	}
+	line, col := pos.Line-p.inputLine+1, pos.Column
+	if line == 1 {
+		switch p.kind {
+		case importInput:
+			col -= len("\t")
+		case exprInput:
+			col -= len(resultVars + " := ")
+		}
+	}
+	return fmt.Sprintf("%d:%d: %s", line, col, msg)
+}
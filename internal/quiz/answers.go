@@ -0,0 +1,98 @@
+package quiz
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Sheet is a student's answers to the theory questions of one file, read
+// from "// Answer: x" comments.
+type Sheet struct {
+	File        string            `json:"file"`
+	Answers     map[string]string `json:"answers"`
+	Diagnostics []Diagnostic      `json:"diagnostics,omitempty"`
+}
+
+// Diagnostic describes a missing, malformed or misplaced answer.
+type Diagnostic struct {
+	Pos      string `json:"pos"` // file:line:column
+	Question string `json:"question,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	if d.Question == "" {
+		return fmt.Sprintf("%s: %s", d.Pos, d.Message)
+	}
+	return fmt.Sprintf("%s: question %s: %s", d.Pos, d.Question, d.Message)
+}
+
+// ExtractAnswers reads the answer sheet a student wrote into a Go source
+// file. An answer is a comment line such as
+//
+//	// Answer: c
+//
+// placed after the question it answers, either in the question's own
+// comment block or in a comment below it. MCQ answers are a choice letter
+// ("c", "c)" or "c) A function ..."); true/false answers are true, false,
+// t or f. src is passed to go/parser.ParseFile, so it may be nil to read
+// filename from disk.
+//
+// Only well-formed answers are put in Answers; every question without one
+// gets a Diagnostic.
+func ExtractAnswers(filename string, src any) (*Sheet, error) {
+	s, err := scanFile(filename, src)
+	if err != nil {
+		return nil, err
+	}
+	sheet := &Sheet{File: filename, Answers: map[string]string{}}
+	for _, m := range s.stray {
+		sheet.Diagnostics = append(sheet.Diagnostics, Diagnostic{
+			Pos:     m.pos.String(),
+			Message: fmt.Sprintf("answer %q does not follow any question", m.value),
+		})
+	}
+	for _, b := range s.blocks {
+		diag := func(pos string, format string, args ...any) {
+			sheet.Diagnostics = append(sheet.Diagnostics, Diagnostic{
+				Pos: pos, Question: b.ID, Message: fmt.Sprintf(format, args...),
+			})
+		}
+		if len(b.answers) == 0 {
+			hint := "b"
+			if b.Kind == TrueFalse {
+				hint = "true"
+			}
+			diag(b.pos.String(), "no answer; add a comment line such as // Answer: %s below the question", hint)
+			continue
+		}
+		for i, m := range b.answers {
+			if i > 0 {
+				diag(m.pos.String(), "answered more than once; only the answer on line %d is used", b.answers[0].pos.Line)
+				continue
+			}
+			if m.value == "" {
+				diag(m.pos.String(), "empty answer")
+				continue
+			}
+			norm, err := b.Normalize(strings.Fields(m.value)[0])
+			if err != nil {
+				diag(m.pos.String(), "malformed answer: %v", err)
+				continue
+			}
+			sheet.Answers[b.ID] = norm
+		}
+	}
+	return sheet, nil
+}
+
+// WriteDiagnostics writes one compiler-style line per diagnostic.
+func (s *Sheet) WriteDiagnostics(w io.Writer) error {
+	var b strings.Builder
+	for _, d := range s.Diagnostics {
+		b.WriteString(d.String() + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
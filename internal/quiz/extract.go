@@ -0,0 +1,202 @@
+package quiz
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+var (
+	sectionLine  = regexp.MustCompile(`(?i)^section\s+(\w+)\s*[:–-]\s*(.*)$`)
+	numberedLine = regexp.MustCompile(`^(\d+)\.\s*(.*)$`)
+	choiceLine   = regexp.MustCompile(`^([a-h])\)\s*(.*)$`)
+	answerLine   = regexp.MustCompile(`(?i)^ans(?:wer)?\s*[:=-]\s*(.*)$`)
+	mcqMarker    = regexp.MustCompile(`(?i)^\(MCQ\)\s*(.*)$`)
+	tfMarker     = regexp.MustCompile(`(?i)^\(True/False\)\s*(.*)$`)
+	tfInline     = regexp.MustCompile(`(?i)^T/F:\s*(.*)$`)
+)
+
+// Extract converts the theory questions written as comments in a Go
+// source file into a quiz. src is passed to go/parser.ParseFile, so it may
+// be nil to read filename from disk.
+//
+// Two layouts are recognised, both used by the assignments:
+//
+//	// 1. (MCQ)                      // 1. What happens when ...?
+//	// What does ... indicate?       //    a) Compilation error
+//	// a) The name of the function   //    b) Runtime error
+//
+//	// 2. (True/False)               // 1. T/F: Go requires braces {} for if blocks
+//	// A function in Go can ...
+//
+// A numbered comment only becomes a question if it is marked (MCQ),
+// (True/False) or T/F:, or if it has lettered choices, so numbered coding
+// exercises are skipped. When questions come from more than one "Section"
+// heading, their IDs are prefixed with the section, e.g. "2.3".
+func Extract(filename string, src any) (*Quiz, error) {
+	s, err := scanFile(filename, src)
+	if err != nil {
+		return nil, err
+	}
+	quiz := &Quiz{Title: filename, Source: filename}
+	for _, b := range s.blocks {
+		quiz.Questions = append(quiz.Questions, b.Question)
+	}
+	if err := quiz.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return quiz, nil
+}
+
+// block is a question found in the comments, with the answer lines that
+// follow it.
+type block struct {
+	Question
+	pos     token.Position
+	answers []answerMark
+}
+
+// answerMark is one "Answer: x" line.
+type answerMark struct {
+	pos   token.Position
+	value string
+}
+
+// scan is the result of reading every comment in a file.
+type scan struct {
+	blocks []*block
+	stray  []answerMark // answer lines that precede every question
+}
+
+// commentLine is one line of comment text and where it starts.
+type commentLine struct {
+	pos  token.Position
+	text string
+}
+
+func scanFile(filename string, src any) (*scan, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &scan{}
+	var (
+		section string
+		cur     *block // question still collecting prompt and choices
+		last    *block // most recent question, which answer lines belong to
+	)
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		cur.Prompt = strings.TrimSpace(cur.Prompt)
+		if len(cur.Choices) > 0 {
+			cur.Kind = MCQ
+		}
+		if cur.Prompt != "" && (cur.Kind == TrueFalse || len(cur.Choices) >= 2) {
+			s.blocks = append(s.blocks, cur)
+			last = cur
+		}
+		cur = nil
+	}
+
+	for _, cg := range f.Comments {
+		for _, line := range commentLines(fset, cg) {
+			if m := answerLine.FindStringSubmatch(line.text); m != nil {
+				flush()
+				mark := answerMark{line.pos, strings.TrimSpace(m[1])}
+				if last == nil {
+					s.stray = append(s.stray, mark)
+				} else {
+					last.answers = append(last.answers, mark)
+				}
+				continue
+			}
+			if m := sectionLine.FindStringSubmatch(line.text); m != nil {
+				flush()
+				section = m[1]
+				last = nil
+				continue
+			}
+			if m := numberedLine.FindStringSubmatch(line.text); m != nil {
+				flush()
+				cur = &block{Question: Question{ID: m[1], Section: section}, pos: line.pos}
+				rest := m[2]
+				switch {
+				case mcqMarker.MatchString(rest):
+					cur.Kind = MCQ
+					cur.Prompt = mcqMarker.FindStringSubmatch(rest)[1]
+				case tfMarker.MatchString(rest):
+					cur.Kind = TrueFalse
+					cur.Prompt = tfMarker.FindStringSubmatch(rest)[1]
+				case tfInline.MatchString(rest):
+					cur.Kind = TrueFalse
+					cur.Prompt = tfInline.FindStringSubmatch(rest)[1]
+				default:
+					cur.Prompt = rest
+				}
+				continue
+			}
+			if cur == nil {
+				continue
+			}
+			if m := choiceLine.FindStringSubmatch(line.text); m != nil {
+				cur.Choices = append(cur.Choices, Choice{Key: m[1], Text: m[2]})
+				continue
+			}
+			if line.text == "" || len(cur.Choices) > 0 {
+				// A blank line or text after the choices ends the question.
+				flush()
+				continue
+			}
+			cur.Prompt += " " + line.text
+		}
+		flush()
+	}
+
+	sections := map[string]bool{}
+	for _, b := range s.blocks {
+		sections[b.Section] = true
+	}
+	for _, b := range s.blocks {
+		if len(sections) > 1 {
+			b.ID = b.Section + "." + b.ID
+		} else {
+			b.Section = ""
+		}
+	}
+	return s, nil
+}
+
+// commentLines splits a comment group into trimmed lines with their
+// positions, dropping banner rules such as "////////" or "------".
+func commentLines(fset *token.FileSet, cg *ast.CommentGroup) []commentLine {
+	var out []commentLine
+	for _, c := range cg.List {
+		pos := fset.Position(c.Pos())
+		text := c.Text
+		if strings.HasPrefix(text, "//") {
+			text = text[2:]
+		} else {
+			text = strings.TrimSuffix(text[2:], "*/")
+		}
+		for i, l := range strings.Split(text, "\n") {
+			p := pos
+			if i > 0 {
+				p.Line += i
+				p.Column = 1
+			}
+			l = strings.TrimSpace(l)
+			if l != "" && strings.Trim(l, "/=-* ") == "" {
+				continue
+			}
+			out = append(out, commentLine{p, l})
+		}
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+package quiz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Take runs the quiz interactively: each question is printed to out and
+// the response read from in. Invalid responses are asked again; an empty
+// line skips the question. It returns the scored answers.
+func (q *Quiz) Take(in io.Reader, out io.Writer) (*Result, error) {
+	sc := bufio.NewScanner(in)
+	answers := map[string]string{}
+	if q.Title != "" {
+		fmt.Fprintf(out, "%s\n%s\n", q.Title, strings.Repeat("=", len([]rune(q.Title))))
+	}
+	for i := range q.Questions {
+		qu := &q.Questions[i]
+		fmt.Fprintf(out, "\n%d/%d. %s\n", i+1, len(q.Questions), qu.Prompt)
+		hint := "[t/f]"
+		if qu.Kind == MCQ {
+			keys := make([]string, len(qu.Choices))
+			for j, c := range qu.Choices {
+				fmt.Fprintf(out, "   %s) %s\n", c.Key, c.Text)
+				keys[j] = c.Key
+			}
+			hint = "[" + strings.Join(keys, "/") + "]"
+		}
+		for {
+			fmt.Fprintf(out, "answer %s: ", hint)
+			if !sc.Scan() {
+				if err := sc.Err(); err != nil {
+					return nil, err
+				}
+				// End of input: the rest stay unanswered.
+				fmt.Fprintln(out)
+				return q.Score(answers), nil
+			}
+			resp := strings.TrimSpace(sc.Text())
+			if resp == "" {
+				break
+			}
+			norm, err := qu.Normalize(resp)
+			if err != nil {
+				fmt.Fprintf(out, "  %v\n", err)
+				continue
+			}
+			answers[qu.ID] = norm
+			switch {
+			case qu.Answer == "":
+			case norm == qu.Answer:
+				fmt.Fprintln(out, "  correct")
+			default:
+				fmt.Fprintf(out, "  wrong, the answer is %s\n", qu.Answer)
+			}
+			break
+		}
+	}
+	return q.Score(answers), nil
+}
@@ -0,0 +1,184 @@
+// Package quiz holds the theory questions from the assignments in a
+// structured form, runs them as an interactive terminal quiz and scores
+// answers against a key.
+//
+// Quizzes are stored as JSON:
+//
+//	{
+//	  "title": "Day 9 – Functions",
+//	  "source": "Assignments/Day9/assignment.go",
+//	  "questions": [
+//	    {"id": "1", "kind": "mcq", "prompt": "...",
+//	     "choices": [{"key": "a", "text": "..."}], "answer": "b"},
+//	    {"id": "2", "kind": "truefalse", "prompt": "...", "answer": "true"}
+//	  ]
+//	}
+//
+// The answer field is the key; it is optional, and unkeyed questions are
+// reported as ungraded.
+package quiz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Kind is the type of a question.
+type Kind string
+
+const (
+	MCQ       Kind = "mcq"
+	TrueFalse Kind = "truefalse"
+)
+
+// Choice is one option of a multiple-choice question.
+type Choice struct {
+	Key  string `json:"key"`
+	Text string `json:"text"`
+}
+
+// Question is a single theory question.
+type Question struct {
+	ID      string   `json:"id"`
+	Kind    Kind     `json:"kind"`
+	Section string   `json:"section,omitempty"`
+	Prompt  string   `json:"prompt"`
+	Choices []Choice `json:"choices,omitempty"`
+	Answer  string   `json:"answer,omitempty"`
+}
+
+// Quiz is an ordered set of questions.
+type Quiz struct {
+	Title     string     `json:"title"`
+	Source    string     `json:"source,omitempty"`
+	Questions []Question `json:"questions"`
+}
+
+// Load reads a quiz from a JSON file and validates it.
+func Load(path string) (*Quiz, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var q Quiz
+	if err := json.Unmarshal(data, &q); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := q.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &q, nil
+}
+
+// Write writes the quiz as indented JSON.
+func (q *Quiz) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(q)
+}
+
+// Validate reports the first malformed question: a duplicate ID, an MCQ
+// with fewer than two choices, or an answer that is not a valid response.
+func (q *Quiz) Validate() error {
+	seen := map[string]bool{}
+	for _, qu := range q.Questions {
+		if qu.ID == "" {
+			return errors.New("question without id")
+		}
+		if seen[qu.ID] {
+			return fmt.Errorf("question %s: duplicate id", qu.ID)
+		}
+		seen[qu.ID] = true
+		switch qu.Kind {
+		case MCQ:
+			if len(qu.Choices) < 2 {
+				return fmt.Errorf("question %s: mcq needs at least two choices", qu.ID)
+			}
+		case TrueFalse:
+		default:
+			return fmt.Errorf("question %s: unknown kind %q", qu.ID, qu.Kind)
+		}
+		if qu.Answer != "" {
+			if _, err := qu.Normalize(qu.Answer); err != nil {
+				return fmt.Errorf("question %s: key: %w", qu.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Find returns the question with the given ID.
+func (q *Quiz) Find(id string) (*Question, bool) {
+	for i := range q.Questions {
+		if q.Questions[i].ID == id {
+			return &q.Questions[i], true
+		}
+	}
+	return nil, false
+}
+
+// ApplyKey sets answers from a key that maps question IDs to answers,
+// overriding any answers stored in the quiz.
+func (q *Quiz) ApplyKey(key map[string]string) error {
+	for id, ans := range key {
+		qu, ok := q.Find(id)
+		if !ok {
+			return fmt.Errorf("key: no question %s", id)
+		}
+		norm, err := qu.Normalize(ans)
+		if err != nil {
+			return fmt.Errorf("key: question %s: %w", id, err)
+		}
+		qu.Answer = norm
+	}
+	return nil
+}
+
+// Normalize converts a response to its canonical form: a lowercase choice
+// key for MCQs, "true" or "false" for true/false questions.
+func (qu *Question) Normalize(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimSuffix(s, ")")
+	s = strings.TrimSuffix(s, ".")
+	switch qu.Kind {
+	case MCQ:
+		for _, c := range qu.Choices {
+			if s == strings.ToLower(c.Key) {
+				return s, nil
+			}
+		}
+		keys := make([]string, len(qu.Choices))
+		for i, c := range qu.Choices {
+			keys[i] = c.Key
+		}
+		return "", fmt.Errorf("%q is not one of %s", s, strings.Join(keys, ", "))
+	case TrueFalse:
+		switch s {
+		case "t", "true":
+			return "true", nil
+		case "f", "false":
+			return "false", nil
+		}
+		return "", fmt.Errorf("%q is not true or false", s)
+	}
+	return "", fmt.Errorf("unknown kind %q", qu.Kind)
+}
+
+// LoadKey reads an answer key: a JSON object mapping question IDs to
+// answers.
+func LoadKey(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var key map[string]string
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
@@ -0,0 +1,112 @@
+package quiz
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// extractMatches checks that extracting the assignment a quiz file names
+// as its source gives back the quiz's questions. The key, title and
+// answers were added to the quiz file by hand and are not compared.
+func extractMatches(t *testing.T, quizFile string) {
+	t.Helper()
+	root := filepath.Join("..", "..")
+	want, err := Load(filepath.Join(root, quizFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Extract(filepath.Join(root, want.Source), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(want.Questions) == 0 {
+		t.Fatalf("%s has no questions", quizFile)
+	}
+	if len(got.Questions) != len(want.Questions) {
+		t.Fatalf("%s: extracted %d questions, want %d", want.Source, len(got.Questions), len(want.Questions))
+	}
+	for i, q := range want.Questions {
+		q.Answer = ""
+		if !reflect.DeepEqual(got.Questions[i], q) {
+			t.Errorf("%s: question %d:\ngot  %+v\nwant %+v", want.Source, i+1, got.Questions[i], q)
+		}
+	}
+}
+
+func TestExtractDay6(t *testing.T) {
+	extractMatches(t, "quizzes/day6.json")
+}
+
+func TestExtractDay9(t *testing.T) {
+	extractMatches(t, "quizzes/day9.json")
+}
+
+const sheet = `package main
+
+// Answer: a
+
+// 1. (MCQ)
+// What does the return type of a function indicate?
+// a) The name of the function
+// b) The type of value the function returns
+// Answer: b) The type of value the function returns
+
+// 2. (True/False)
+// A function in Go can return more than one value.
+// Answer: T
+
+// 3. (MCQ)
+// Which keyword is used to return a value from a function?
+// a) break
+// b) return
+// Answer: e
+
+// 4. (True/False)
+// A recursive function must have a base case.
+// Answer: maybe
+
+// 5. (MCQ)
+// What is a variadic function?
+// a) A function with no parameters
+// b) A function that takes a variable number of arguments
+// Answer: b
+// Answer: a
+
+// 6. (True/False)
+// Variadic parameters are treated as slices inside the function.
+// Answer:
+
+// 7. (MCQ)
+// What is an anonymous function?
+// a) A function without a name
+// b) A recursive function
+
+func main() {}
+`
+
+func TestExtractAnswers(t *testing.T) {
+	s, err := ExtractAnswers("sheet.go", sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantAnswers := map[string]string{"1": "b", "2": "true", "5": "b"}
+	if !reflect.DeepEqual(s.Answers, wantAnswers) {
+		t.Errorf("Answers = %v, want %v", s.Answers, wantAnswers)
+	}
+	var b strings.Builder
+	if err := s.WriteDiagnostics(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `sheet.go:3:1: answer "a" does not follow any question
+sheet.go:19:1: question 3: malformed answer: "e" is not one of a, b
+sheet.go:23:1: question 4: malformed answer: "maybe" is not true or false
+sheet.go:30:1: question 5: answered more than once; only the answer on line 29 is used
+sheet.go:34:1: question 6: empty answer
+sheet.go:36:1: question 7: no answer; add a comment line such as // Answer: b below the question
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteDiagnostics:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package quiz
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Status is the outcome of one answered question.
+type Status string
+
+const (
+	Correct    Status = "correct"
+	Wrong      Status = "wrong"
+	Unanswered Status = "unanswered"
+	Invalid    Status = "invalid"  // the response is not a valid choice
+	Ungraded   Status = "ungraded" // the quiz has no key for the question
+)
+
+// Mark is the result for one question.
+type Mark struct {
+	ID     string `json:"id"`
+	Given  string `json:"given,omitempty"`
+	Answer string `json:"answer,omitempty"`
+	Status Status `json:"status"`
+	Note   string `json:"note,omitempty"`
+}
+
+// Result is a scored set of answers.
+type Result struct {
+	Marks []Mark `json:"marks"`
+	Score int    `json:"score"`
+	Total int    `json:"total"` // number of keyed questions
+}
+
+// Score grades answers, which map question IDs to responses, against the
+// quiz's key.
+func (q *Quiz) Score(answers map[string]string) *Result {
+	r := &Result{}
+	for i := range q.Questions {
+		qu := &q.Questions[i]
+		m := Mark{ID: qu.ID, Given: answers[qu.ID], Answer: qu.Answer}
+		if qu.Answer != "" {
+			r.Total++
+		}
+		switch norm, err := qu.Normalize(m.Given); {
+		case strings.TrimSpace(m.Given) == "":
+			m.Status = Unanswered
+		case err != nil:
+			m.Status, m.Note = Invalid, err.Error()
+		case qu.Answer == "":
+			m.Given, m.Status = norm, Ungraded
+		case norm == qu.Answer:
+			m.Given, m.Status = norm, Correct
+			r.Score++
+		default:
+			m.Given, m.Status = norm, Wrong
+		}
+		r.Marks = append(r.Marks, m)
+	}
+	return r
+}
+
+// WriteText writes a one-line-per-question summary of r.
+func (r *Result) WriteText(w io.Writer) error {
+	var b strings.Builder
+	for _, m := range r.Marks {
+		line := fmt.Sprintf("  %-5s %-10s", m.ID, m.Status)
+		switch m.Status {
+		case Wrong:
+			line += fmt.Sprintf(" answered %s, correct is %s", m.Given, m.Answer)
+		case Invalid:
+			line += " " + m.Note
+		case Unanswered:
+			if m.Answer != "" {
+				line += " correct is " + m.Answer
+			}
+		}
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	fmt.Fprintf(&b, "Score: %d/%d\n", r.Score, r.Total)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,175 @@
+package scopetree
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"html"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WriteHTML writes a self-contained page with the scope tree next to the
+// source. Shadowing declarations and the ones they hide are highlighted in
+// both, and every position links to its line in the source.
+func (t *Tree) WriteHTML(w io.Writer) error {
+	index := map[string]int{}
+	for i, name := range t.Files {
+		index[name] = i
+	}
+	anchor := func(p token.Position) string {
+		i, ok := index[p.Filename]
+		if !ok || !p.IsValid() {
+			return ""
+		}
+		return fmt.Sprintf("f%d-L%d", i, p.Line)
+	}
+
+	// Mark every shadowing and shadowed identifier in the source.
+	marks := map[string][]mark{}
+	for _, s := range t.Shadows {
+		for _, m := range []struct {
+			d     *Decl
+			class string
+		}{{s.Inner, "inner"}, {s.Outer, "outer"}} {
+			if m.d.Pos.IsValid() {
+				marks[anchor(m.d.Pos)] = append(marks[anchor(m.d.Pos)], mark{m.d.Pos.Column, len(m.d.Name), m.class})
+			}
+		}
+	}
+
+	var files []sourceFile
+	for i, name := range t.Files {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		f := sourceFile{Name: filepath.Base(name)}
+		for n, line := range bytes.Split(bytes.TrimSuffix(src, []byte("\n")), []byte("\n")) {
+			id := fmt.Sprintf("f%d-L%d", i, n+1)
+			f.Lines = append(f.Lines, sourceLine{ID: id, N: n + 1, HTML: highlight(line, marks[id])})
+		}
+		files = append(files, f)
+	}
+
+	tmpl, err := template.New("page").Funcs(template.FuncMap{
+		"anchor": anchor,
+		"site":   site,
+		"span":   span,
+	}).Parse(page)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	return tmpl.Execute(w, map[string]any{
+		"Title":   strings.Join(names, ", "),
+		"Root":    t.Root,
+		"Shadows": t.Shadows,
+		"Errors":  t.Errors,
+		"Files":   files,
+	})
+}
+
+type mark struct {
+	col, len int // 1-based byte column and length of the identifier
+	class    string
+}
+
+type sourceFile struct {
+	Name  string
+	Lines []sourceLine
+}
+
+type sourceLine struct {
+	ID   string
+	N    int
+	HTML template.HTML
+}
+
+// highlight escapes line and wraps each marked identifier in a span.
+func highlight(line []byte, marks []mark) template.HTML {
+	sort.Slice(marks, func(i, j int) bool { return marks[i].col < marks[j].col })
+	var b bytes.Buffer
+	at := 0
+	for _, m := range marks {
+		start := m.col - 1
+		if start < at || start+m.len > len(line) {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(line[at:start])))
+		fmt.Fprintf(&b, `<span class="%s">%s</span>`, m.class, html.EscapeString(string(line[start:start+m.len])))
+		at = start + m.len
+	}
+	b.WriteString(html.EscapeString(string(line[at:])))
+	return template.HTML(b.String())
+}
+
+const page = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Scopes of {{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 1.5em; color: #222; }
+code, pre, .tree { font-family: ui-monospace, Menlo, Consolas, monospace; font-size: 14px; }
+.panes { display: flex; gap: 2em; align-items: flex-start; }
+.tree, .source { flex: 1; min-width: 0; overflow-x: auto; }
+.source { position: sticky; top: 0; max-height: 95vh; overflow-y: auto; }
+details { margin-left: 1.2em; border-left: 1px solid #ccc; padding-left: .6em; }
+summary { cursor: pointer; font-weight: bold; }
+summary .pos, .decl .pos { font-weight: normal; color: #888; }
+ul.decls { list-style: none; margin: .2em 0; padding-left: 1.2em; }
+.decl.inner { background: #fde2e1; }
+.decl.outer { background: #fff3c4; }
+.note { color: #a33; }
+pre { margin: 0; }
+.line { display: block; }
+.line:target { background: #e4efff; }
+.ln { display: inline-block; width: 3em; color: #aaa; text-align: right; margin-right: 1em; text-decoration: none; }
+span.inner { background: #f8b4b1; font-weight: bold; }
+span.outer { background: #ffe27a; font-weight: bold; }
+a { color: #0b5cad; }
+</style>
+</head>
+<body>
+<h1>Scopes of {{.Title}}</h1>
+{{with .Shadows}}
+<h2>Shadowed names</h2>
+<ul>
+{{range .}}<li><code>{{.Inner.Name}}</code> declared at <a href="#{{anchor .Inner.Pos}}">{{site .Inner.Pos}}</a> ({{.Inner.Scope}})
+ shadows {{if anchor .Outer.Pos}}<a href="#{{anchor .Outer.Pos}}">{{site .Outer.Pos}}</a>{{else}}the predeclared {{.Outer.Kind}}{{end}} ({{.Outer.Scope}})</li>
+{{end}}</ul>
+<p><span class="inner">Red</span>: the inner declaration. <span class="outer">Yellow</span>: the declaration it hides.</p>
+{{else}}
+<p>No name is shadowed.</p>
+{{end}}
+{{with .Errors}}
+<h2>Type errors</h2>
+<ul>{{range .}}<li><code>{{.}}</code></li>{{end}}</ul>
+{{end}}
+<div class="panes">
+<div class="tree">{{template "scope" .Root}}</div>
+<div class="source">
+{{range .Files}}<h3>{{.Name}}</h3>
+<pre>{{range .Lines}}<span class="line" id="{{.ID}}"><a class="ln" href="#{{.ID}}">{{.N}}</a>{{.HTML}}</span>{{end}}</pre>
+{{end}}</div>
+</div>
+</body>
+</html>
+{{define "scope"}}<details{{if ne .Kind "universe"}} open{{end}}>
+<summary>{{.Label}}{{if .Pos.IsValid}} <span class="pos">{{span .Pos .End}}</span>{{end}}{{if .Hidden}} <span class="pos">({{.Hidden}} more predeclared names, not used)</span>{{end}}</summary>
+{{with .Decls}}<ul class="decls">
+{{range .}}<li class="decl{{if .Shadows}} inner{{else if .ShadowedBy}} outer{{end}}">{{.Label}}{{if .Pos.IsValid}} <a class="pos" href="#{{anchor .Pos}}">{{site .Pos}}</a>{{end}}
+{{- with .Shadows}} <span class="note">shadows {{if anchor .Pos}}<a href="#{{anchor .Pos}}">{{site .Pos}}</a>{{else}}the predeclared {{.Name}}{{end}}</span>{{end}}
+{{- with .ShadowedBy}} <span class="note">shadowed at {{range $i, $d := .}}{{if $i}}, {{end}}<a href="#{{anchor $d.Pos}}">{{site $d.Pos}}</a>{{end}}</span>{{end}}</li>
+{{end}}</ul>{{end}}
+{{range .Children}}{{template "scope" .}}{{end}}
+</details>{{end}}
+`
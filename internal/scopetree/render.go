@@ -0,0 +1,122 @@
+package scopetree
+
+import (
+	"bufio"
+	"fmt"
+	"go/token"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// WriteText writes the tree with one scope or declaration per line. A
+// declaration that shadows another is marked with "!" and names the
+// declaration it hides; a shadowed one lists where it is shadowed. The
+// shadowed names are summarised after the tree.
+func (t *Tree) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	writeScope(bw, t.Root, "", "")
+	if len(t.Shadows) > 0 {
+		fmt.Fprintf(bw, "\nShadowed names (%d):\n", len(t.Shadows))
+		for _, s := range t.Shadows {
+			fmt.Fprintf(bw, "  %-10s %s (%s) shadows %s\n", s.Inner.Name, site(s.Inner.Pos), s.Inner.Scope, where(s.Outer))
+		}
+	}
+	if len(t.Errors) > 0 {
+		fmt.Fprintf(bw, "\nType errors (%d), the tree is built anyway:\n", len(t.Errors))
+		for _, err := range t.Errors {
+			fmt.Fprintf(bw, "  %v\n", err)
+		}
+	}
+	return bw.Flush()
+}
+
+func writeScope(w *bufio.Writer, s *Scope, first, rest string) {
+	fmt.Fprintf(w, "%s%s", first, s.Label())
+	if s.Pos.IsValid() {
+		fmt.Fprintf(w, "  %s", span(s.Pos, s.End))
+	}
+	if s.Hidden > 0 {
+		fmt.Fprintf(w, "  (%d more predeclared names, not used)", s.Hidden)
+	}
+	fmt.Fprintln(w)
+
+	declIndent := rest + "    "
+	if len(s.Children) > 0 {
+		declIndent = rest + "│   "
+	}
+	for _, d := range s.Decls {
+		mark := "  "
+		if d.Shadows != nil {
+			mark = "! "
+		}
+		fmt.Fprintf(w, "%s%s%s", declIndent, mark, d.Label())
+		if d.Pos.IsValid() {
+			fmt.Fprintf(w, "  %s", site(d.Pos))
+		}
+		if d.Shadows != nil {
+			fmt.Fprintf(w, "  shadows %s", where(d.Shadows))
+		}
+		if len(d.ShadowedBy) > 0 {
+			var at []string
+			for _, by := range d.ShadowedBy {
+				at = append(at, site(by.Pos))
+			}
+			fmt.Fprintf(w, "  shadowed at %s", strings.Join(at, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+	for i, c := range s.Children {
+		if i == len(s.Children)-1 {
+			writeScope(w, c, rest+"└── ", rest+"    ")
+		} else {
+			writeScope(w, c, rest+"├── ", rest+"│   ")
+		}
+	}
+}
+
+// Label is the scope's kind followed by its name, e.g. "func main".
+func (s *Scope) Label() string {
+	if s.Name == "" {
+		return s.Kind
+	}
+	return s.Kind + " " + s.Name
+}
+
+// Label is the declaration as it could be written in Go, e.g. "var x int".
+func (d *Decl) Label() string {
+	switch d.Kind {
+	case "package", "builtin", "nil":
+		return d.Kind + " " + d.Name
+	case "func":
+		return "func " + d.Name + strings.TrimPrefix(d.Type, "func")
+	}
+	if d.Type == "" {
+		return d.Kind + " " + d.Name
+	}
+	return d.Kind + " " + d.Name + " " + d.Type
+}
+
+// where describes a shadowed declaration.
+func where(d *Decl) string {
+	if !d.Pos.IsValid() {
+		return "the predeclared " + d.Name
+	}
+	return fmt.Sprintf("%s (%s)", site(d.Pos), d.Scope)
+}
+
+// site prints a declaration position as file:line:col, or "predeclared".
+func site(p token.Position) string {
+	if !p.IsValid() {
+		return "(predeclared)"
+	}
+	return fmt.Sprintf("%s:%d:%d", filepath.Base(p.Filename), p.Line, p.Column)
+}
+
+// span prints the lines a scope covers.
+func span(from, to token.Position) string {
+	if from.Line == to.Line {
+		return fmt.Sprintf("%s:%d", filepath.Base(from.Filename), from.Line)
+	}
+	return fmt.Sprintf("%s:%d-%d", filepath.Base(from.Filename), from.Line, to.Line)
+}
@@ -0,0 +1,350 @@
+// Package scopetree builds the tree of lexical scopes of a Go file or
+// package, as go/types sees it: universe, package, file, function and
+// every nested block, each with the names declared in it. A declaration
+// that hides a name from an enclosing scope, like x := 20 inside a block
+// that can already see x, is marked as shadowing it and both declaration
+// sites are recorded.
+package scopetree
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Scope is one lexical scope.
+type Scope struct {
+	Kind     string         `json:"kind"`           // universe, package, file, func, if, for, block...
+	Name     string         `json:"name,omitempty"` // package, file or function name
+	Pos      token.Position `json:"pos"`            // zero for the universe and package scopes
+	End      token.Position `json:"end"`
+	Decls    []*Decl        `json:"decls,omitempty"`
+	Children []*Scope       `json:"children,omitempty"`
+
+	// Hidden counts the universe's predeclared names left out of Decls
+	// because the source neither uses nor shadows them.
+	Hidden int `json:"hidden,omitempty"`
+}
+
+// Decl is a name declared in a scope.
+type Decl struct {
+	Name string         `json:"name"`
+	Kind string         `json:"kind"` // var, const, type, func, package, builtin, nil
+	Type string         `json:"type,omitempty"`
+	Pos  token.Position `json:"pos"` // zero for predeclared names
+
+	// Scope is the label of the declaring scope, e.g. "func main".
+	Scope string `json:"scope"`
+
+	// Shadows is the declaration this one hides, if any.
+	Shadows *Decl `json:"shadows,omitempty"`
+	// ShadowedBy lists the declarations that hide this one.
+	ShadowedBy []*Decl `json:"-"`
+}
+
+// Shadow is a pair of declarations of the same name where Inner hides
+// Outer for the rest of Inner's scope.
+type Shadow struct {
+	Inner, Outer *Decl
+}
+
+// Tree is the scope tree of a package.
+type Tree struct {
+	Fset    *token.FileSet
+	Files   []string // file names, as given to Load
+	Root    *Scope   // the universe scope
+	Shadows []Shadow // in source order of Inner
+	Errors  []error  // type errors; the tree is built anyway
+}
+
+// Load parses and type-checks a Go file, or the non-test files of the
+// package in a directory, and builds its scope tree. Type errors such as
+// unused variables do not stop it; they are returned in Tree.Errors.
+func Load(path string) (*Tree, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if fi.IsDir() {
+		matches, err := filepath.Glob(filepath.Join(path, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		files = files[:0]
+		for _, m := range matches {
+			if !strings.HasSuffix(m, "_test.go") {
+				files = append(files, m)
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no Go files in %s", path)
+		}
+	}
+
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for _, name := range files {
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, f)
+	}
+	return Build(fset, parsed)
+}
+
+// Build type-checks files and builds their scope tree.
+func Build(fset *token.FileSet, files []*ast.File) (*Tree, error) {
+	if len(files) == 0 {
+		return nil, errors.New("no files")
+	}
+	t := &Tree{Fset: fset}
+	for _, f := range files {
+		t.Files = append(t.Files, fset.File(f.Pos()).Name())
+	}
+	info := &types.Info{
+		Scopes: map[ast.Node]*types.Scope{},
+		Defs:   map[*ast.Ident]types.Object{},
+		Uses:   map[*ast.Ident]types.Object{},
+	}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(err error) { t.Errors = append(t.Errors, err) },
+	}
+	pkg, _ := conf.Check(files[0].Name.Name, fset, files, info)
+	if pkg == nil {
+		return nil, errors.Join(t.Errors...)
+	}
+
+	b := &builder{
+		tree:  t,
+		info:  info,
+		nodes: map[*types.Scope]ast.Node{},
+		funcs: map[*ast.FuncType]string{},
+		decls: map[types.Object]*Decl{},
+		used:  map[types.Object]bool{},
+		qual:  types.RelativeTo(pkg),
+	}
+	for node, s := range info.Scopes {
+		b.nodes[s] = node
+	}
+	for _, f := range files {
+		for _, d := range f.Decls {
+			if fn, ok := d.(*ast.FuncDecl); ok {
+				name := fn.Name.Name
+				if fn.Recv != nil && len(fn.Recv.List) > 0 {
+					name = "(" + types.ExprString(fn.Recv.List[0].Type) + ")." + name
+				}
+				b.funcs[fn.Type] = name
+			}
+		}
+	}
+	for _, obj := range info.Uses {
+		b.used[obj] = true
+	}
+
+	t.Root = b.scope(types.Universe)
+	pkgScope := b.scope(pkg.Scope())
+	pkgScope.Kind, pkgScope.Name = "package", pkg.Name()
+	t.Root.Children = []*Scope{pkgScope}
+	t.Root.walk(func(s *Scope) {
+		for _, d := range s.Decls {
+			d.Scope = s.Label()
+		}
+	})
+
+	sort.Slice(t.Shadows, func(i, j int) bool {
+		return before(t.Shadows[i].Inner.Pos, t.Shadows[j].Inner.Pos)
+	})
+	return t, nil
+}
+
+type builder struct {
+	tree  *Tree
+	info  *types.Info
+	nodes map[*types.Scope]ast.Node
+	funcs map[*ast.FuncType]string
+	decls map[types.Object]*Decl
+	used  map[types.Object]bool
+	qual  types.Qualifier
+}
+
+func (b *builder) scope(s *types.Scope) *Scope {
+	out := &Scope{}
+	node := b.nodes[s]
+	switch {
+	case s == types.Universe:
+		out.Kind = "universe"
+	case node == nil:
+		// the package scope, named by the caller
+	default:
+		out.Kind, out.Name = kindOf(node, b.funcs)
+		out.Pos, out.End = b.tree.Fset.Position(s.Pos()), b.tree.Fset.Position(s.End())
+		if f, ok := node.(*ast.File); ok {
+			out.Pos, out.End = b.tree.Fset.Position(f.FileStart), b.tree.Fset.Position(f.FileEnd)
+			out.Name = filepath.Base(out.Pos.Filename)
+		}
+	}
+
+	for _, name := range s.Names() {
+		obj := s.Lookup(name)
+		if s == types.Universe && !b.used[obj] {
+			// Listed only if shadowed, which the inner scope records.
+			out.Hidden++
+			continue
+		}
+		out.Decls = append(out.Decls, b.decl(s, obj))
+	}
+	sort.SliceStable(out.Decls, func(i, j int) bool {
+		return before(out.Decls[i].Pos, out.Decls[j].Pos)
+	})
+
+	for i := range s.NumChildren() {
+		out.Children = append(out.Children, b.scope(s.Child(i)))
+	}
+	sort.SliceStable(out.Children, func(i, j int) bool {
+		return before(out.Children[i].Pos, out.Children[j].Pos)
+	})
+	return out
+}
+
+// decl records obj, declared in s, and whether it shadows a declaration
+// of the same name in an enclosing scope.
+func (b *builder) decl(s *types.Scope, obj types.Object) *Decl {
+	if d := b.decls[obj]; d != nil {
+		return d
+	}
+	d := &Decl{Name: obj.Name(), Kind: kindOfObject(obj), Pos: b.tree.Fset.Position(obj.Pos())}
+	switch obj.(type) {
+	case *types.TypeName:
+		if obj.Parent() != types.Universe {
+			d.Type = types.TypeString(obj.Type().Underlying(), b.qual)
+			if obj.(*types.TypeName).IsAlias() {
+				d.Type = "= " + types.TypeString(obj.Type(), b.qual)
+			}
+		}
+	case *types.Var, *types.Const, *types.Func:
+		d.Type = types.TypeString(obj.Type(), b.qual)
+	}
+	b.decls[obj] = d
+
+	if s.Parent() == nil || obj.Name() == "_" {
+		return d
+	}
+	// Imports live in the file scope, and a package-level name with the
+	// same name is a compile error, not shadowing.
+	if _, ok := obj.(*types.PkgName); ok {
+		return d
+	}
+	_, outer := s.Parent().LookupParent(obj.Name(), obj.Pos())
+	if outer == nil {
+		return d
+	}
+	od := b.decls[outer]
+	if od == nil {
+		// Usually a predeclared name: record it so the universe lists it.
+		od = b.decl(outer.Parent(), outer)
+		if outer.Parent() == types.Universe {
+			b.tree.Root.addUniverse(od)
+		}
+	}
+	d.Shadows = od
+	od.ShadowedBy = append(od.ShadowedBy, d)
+	b.tree.Shadows = append(b.tree.Shadows, Shadow{Inner: d, Outer: od})
+	return d
+}
+
+// addUniverse lists a predeclared name that the universe scope first
+// left out as unused. The universe is built before anything it could be
+// added to, so Root is set by then.
+func (s *Scope) addUniverse(d *Decl) {
+	for _, e := range s.Decls {
+		if e == d {
+			return
+		}
+	}
+	s.Decls = append(s.Decls, d)
+	s.Hidden--
+	sort.SliceStable(s.Decls, func(i, j int) bool { return s.Decls[i].Name < s.Decls[j].Name })
+}
+
+// walk calls fn for s and every scope below it, parents first.
+func (s *Scope) walk(fn func(*Scope)) {
+	fn(s)
+	for _, c := range s.Children {
+		c.walk(fn)
+	}
+}
+
+func kindOf(node ast.Node, funcs map[*ast.FuncType]string) (kind, name string) {
+	switch n := node.(type) {
+	case *ast.File:
+		return "file", ""
+	case *ast.FuncType:
+		if name, ok := funcs[n]; ok {
+			return "func", name
+		}
+		return "func", "literal"
+	case *ast.IfStmt:
+		return "if", ""
+	case *ast.ForStmt, *ast.RangeStmt:
+		return "for", ""
+	case *ast.SwitchStmt:
+		return "switch", ""
+	case *ast.TypeSwitchStmt:
+		return "type switch", ""
+	case *ast.CaseClause:
+		if n.List == nil {
+			return "default", ""
+		}
+		return "case", ""
+	case *ast.SelectStmt:
+		return "select", ""
+	case *ast.CommClause:
+		if n.Comm == nil {
+			return "default", ""
+		}
+		return "case", ""
+	case *ast.BlockStmt:
+		return "block", ""
+	}
+	return fmt.Sprintf("%T", node), ""
+}
+
+func kindOfObject(obj types.Object) string {
+	switch obj.(type) {
+	case *types.Var:
+		return "var"
+	case *types.Const:
+		return "const"
+	case *types.TypeName:
+		return "type"
+	case *types.Func:
+		return "func"
+	case *types.PkgName:
+		return "package"
+	case *types.Builtin:
+		return "builtin"
+	case *types.Nil:
+		return "nil"
+	}
+	return "object"
+}
+
+// before orders positions by file, then offset; the zero position of a
+// predeclared name sorts first.
+func before(a, b token.Position) bool {
+	if a.Filename != b.Filename {
+		return a.Filename < b.Filename
+	}
+	return a.Offset < b.Offset
+}
@@ -0,0 +1,144 @@
+package scopetree
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/shadow.html from the current output")
+
+func load(t *testing.T) *Tree {
+	t.Helper()
+	tree, err := Load("testdata/shadow.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.Errors) > 0 {
+		t.Fatalf("type errors: %v", tree.Errors)
+	}
+	return tree
+}
+
+func TestShadows(t *testing.T) {
+	tree := load(t)
+	var got []string
+	for _, s := range tree.Shadows {
+		outer := "predeclared"
+		if s.Outer.Pos.IsValid() {
+			outer = fmt.Sprintf("%d (%s)", s.Outer.Pos.Line, s.Outer.Scope)
+		}
+		got = append(got, fmt.Sprintf("%s %d (%s) shadows %s", s.Inner.Name, s.Inner.Pos.Line, s.Inner.Scope, outer))
+	}
+	// The imports errors and fmt are never counted, nor is the redeclared
+	// n, err := in the same scope.
+	want := []string{
+		"x 19 (func main) shadows 8 (package main)",
+		"x 21 (block) shadows 19 (func main)",
+		"n 28 (if) shadows 24 (func main)",
+		"err 28 (if) shadows 24 (func main)",
+		"len 32 (block) shadows predeclared",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Shadows:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for _, s := range tree.Shadows {
+		if s.Inner.Shadows != s.Outer {
+			t.Errorf("%s at line %d: Shadows does not point at the outer declaration", s.Inner.Name, s.Inner.Pos.Line)
+		}
+		found := false
+		for _, d := range s.Outer.ShadowedBy {
+			found = found || d == s.Inner
+		}
+		if !found {
+			t.Errorf("%s at line %d: missing from the outer declaration's ShadowedBy", s.Inner.Name, s.Inner.Pos.Line)
+		}
+	}
+}
+
+// shape writes the scopes below s as indented labels with their lines
+// and declared names.
+func shape(b *strings.Builder, s *Scope, indent string) {
+	fmt.Fprintf(b, "%s%s", indent, s.Label())
+	if s.Pos.IsValid() {
+		fmt.Fprintf(b, " %d-%d", s.Pos.Line, s.End.Line)
+	}
+	for _, d := range s.Decls {
+		fmt.Fprintf(b, " %s", d.Name)
+	}
+	b.WriteByte('\n')
+	for _, c := range s.Children {
+		shape(b, c, indent+"  ")
+	}
+}
+
+func TestTreeShape(t *testing.T) {
+	tree := load(t)
+	if tree.Root.Kind != "universe" || tree.Root.Hidden == 0 {
+		t.Errorf("root is %s with %d hidden names, want the universe with its unused names hidden", tree.Root.Kind, tree.Root.Hidden)
+	}
+	var b strings.Builder
+	shape(&b, tree.Root, "")
+	want := `universe error int len nil string
+  package main x parse main
+    file shadow.go 1-35 errors fmt
+      func parse 10-15 s
+        if 11-13
+          block 11-13
+      func main 17-35 x n err
+        block 20-23 x
+        if 25-27
+          block 25-27
+        if 28-30 n err
+          block 28-30
+        for 31-34 i
+          block 31-34 len
+`
+	if got := b.String(); got != want {
+		t.Errorf("tree:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	tree := load(t)
+	var b bytes.Buffer
+	if err := tree.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"builtin len  shadowed at shadow.go:32:3",
+		"! var x int  shadow.go:21:3  shadows shadow.go:19:2 (func main)",
+		"! var len int  shadow.go:32:3  shadows the predeclared len",
+		"Shadowed names (5):",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteText output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	tree := load(t)
+	var b bytes.Buffer
+	if err := tree.WriteHTML(&b); err != nil {
+		t.Fatal(err)
+	}
+	const golden = "testdata/shadow.html"
+	if *update {
+		if err := os.WriteFile(golden, b.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(b.Bytes(), want) {
+		t.Errorf("WriteHTML output differs from %s (run go test -update to accept it):\n%s", golden, b.Bytes())
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+var x = 10
+
+func parse(s string) (int, error) {
+	if s == "" {
+		return 0, errors.New("empty")
+	}
+	return len(s), nil
+}
+
+func main() {
+	fmt.Println(x)
+	x := 20
+	{
+		x := 30
+		fmt.Println(x)
+	}
+	n, err := parse("go")
+	if err != nil {
+		return
+	}
+	if n, err := parse(""); err != nil {
+		fmt.Println(n, err)
+	}
+	for i := range 3 {
+		len := i * 2
+		fmt.Println(len, x, n)
+	}
+}
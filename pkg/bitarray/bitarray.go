@@ -0,0 +1,296 @@
+// Package bitarray is a bit array of any length, the bitwise operators of
+// Codes/Day7/04_BitwiseOperators carried past a single machine word. It
+// suits feature-flag tables, bloom-style filters and sets of small
+// integers, where a map[int]bool spends tens of bytes per entry and a
+// BitArray one bit (see the benchmarks in bitarray_test.go).
+//
+// The operators follow math/big: the receiver is the result, the
+// operands are arguments, and the receiver is returned, so
+//
+//	z.And(x, y)   // z = x & y
+//	x.And(x, y)   // x &= y
+//
+// Operands of a binary operator must have the same length. Bit i of a
+// BitArray corresponds to bit i of its words, so Shift by a positive n
+// moves bits to higher indices like << does.
+package bitarray
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// BitArray is a fixed-length array of bits, all clear initially. The zero
+// value is an empty array; use New or Resize to give it a length.
+type BitArray struct {
+	words []uint64
+	n     int // length in bits; the bits of words past n are always 0
+}
+
+// New returns a BitArray of n clear bits.
+func New(n int) *BitArray {
+	if n < 0 {
+		panic(fmt.Sprintf("bitarray: negative length %d", n))
+	}
+	return &BitArray{words: make([]uint64, wordsFor(n)), n: n}
+}
+
+// Of returns a BitArray of length n with the given bits set.
+func Of(n int, set ...int) *BitArray {
+	a := New(n)
+	for _, i := range set {
+		a.Set(i)
+	}
+	return a
+}
+
+func wordsFor(n int) int { return (n + 63) / 64 }
+
+// Len returns the number of bits.
+func (a *BitArray) Len() int { return a.n }
+
+// Resize changes the length to n. Bits below n keep their value; new
+// bits are clear.
+func (a *BitArray) Resize(n int) {
+	if n < 0 {
+		panic(fmt.Sprintf("bitarray: negative length %d", n))
+	}
+	old := a.n
+	a.setLen(n)
+	if n > old {
+		// Words kept from a longer array may hold stale bits.
+		a.clearRange(old, n)
+	} else {
+		a.trim()
+	}
+}
+
+// setLen sets the length to n, reusing the words when they are long
+// enough. The content of the words is unspecified.
+func (a *BitArray) setLen(n int) {
+	nw := wordsFor(n)
+	if cap(a.words) < nw {
+		words := make([]uint64, nw)
+		copy(words, a.words)
+		a.words = words
+	}
+	a.words = a.words[:nw]
+	a.n = n
+}
+
+// trim clears the bits of the last word past the length.
+func (a *BitArray) trim() {
+	if r := a.n % 64; r != 0 {
+		a.words[len(a.words)-1] &= 1<<r - 1
+	}
+}
+
+func (a *BitArray) check(i int) {
+	if i < 0 || i >= a.n {
+		panic(fmt.Sprintf("bitarray: index %d out of range [0:%d]", i, a.n))
+	}
+}
+
+func (a *BitArray) checkRange(lo, hi int) {
+	if lo < 0 || hi < lo || hi > a.n {
+		panic(fmt.Sprintf("bitarray: range [%d:%d] out of range [0:%d]", lo, hi, a.n))
+	}
+}
+
+// Get reports whether bit i is set.
+func (a *BitArray) Get(i int) bool {
+	a.check(i)
+	return a.words[i/64]>>(i%64)&1 != 0
+}
+
+// Set sets bit i.
+func (a *BitArray) Set(i int) {
+	a.check(i)
+	a.words[i/64] |= 1 << (i % 64)
+}
+
+// Clear clears bit i.
+func (a *BitArray) Clear(i int) {
+	a.check(i)
+	a.words[i/64] &^= 1 << (i % 64)
+}
+
+// Flip flips bit i.
+func (a *BitArray) Flip(i int) {
+	a.check(i)
+	a.words[i/64] ^= 1 << (i % 64)
+}
+
+// SetTo sets bit i to v.
+func (a *BitArray) SetTo(i int, v bool) {
+	if v {
+		a.Set(i)
+	} else {
+		a.Clear(i)
+	}
+}
+
+// SetRange sets bits lo through hi-1.
+func (a *BitArray) SetRange(lo, hi int) {
+	a.checkRange(lo, hi)
+	a.eachWord(lo, hi, func(w *uint64, m uint64) { *w |= m })
+}
+
+// ClearRange clears bits lo through hi-1.
+func (a *BitArray) ClearRange(lo, hi int) {
+	a.checkRange(lo, hi)
+	a.clearRange(lo, hi)
+}
+
+func (a *BitArray) clearRange(lo, hi int) {
+	a.eachWord(lo, hi, func(w *uint64, m uint64) { *w &^= m })
+}
+
+// FlipRange flips bits lo through hi-1.
+func (a *BitArray) FlipRange(lo, hi int) {
+	a.checkRange(lo, hi)
+	a.eachWord(lo, hi, func(w *uint64, m uint64) { *w ^= m })
+}
+
+// eachWord calls f with every word holding bits lo through hi-1 and the
+// mask of those bits in it.
+func (a *BitArray) eachWord(lo, hi int, f func(w *uint64, mask uint64)) {
+	for lo < hi {
+		wi, off := lo/64, lo%64
+		n := min(64-off, hi-lo)
+		f(&a.words[wi], ^uint64(0)>>(64-n)<<off)
+		lo += n
+	}
+}
+
+// Clone returns a copy of a.
+func (a *BitArray) Clone() *BitArray {
+	return &BitArray{words: append([]uint64(nil), a.words...), n: a.n}
+}
+
+// Equal reports whether a and b have the same length and bits.
+func (a *BitArray) Equal(b *BitArray) bool {
+	if a.n != b.n {
+		return false
+	}
+	for i, w := range a.words {
+		if w != b.words[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// binary prepares z to receive the result of an operator on x and y.
+func (z *BitArray) binary(op string, x, y *BitArray) {
+	if x.n != y.n {
+		panic(fmt.Sprintf("bitarray: %s of arrays of length %d and %d", op, x.n, y.n))
+	}
+	z.setLen(x.n)
+}
+
+// And sets z to x & y and returns z.
+func (z *BitArray) And(x, y *BitArray) *BitArray {
+	z.binary("And", x, y)
+	for i := range z.words {
+		z.words[i] = x.words[i] & y.words[i]
+	}
+	return z
+}
+
+// Or sets z to x | y and returns z.
+func (z *BitArray) Or(x, y *BitArray) *BitArray {
+	z.binary("Or", x, y)
+	for i := range z.words {
+		z.words[i] = x.words[i] | y.words[i]
+	}
+	return z
+}
+
+// Xor sets z to x ^ y and returns z.
+func (z *BitArray) Xor(x, y *BitArray) *BitArray {
+	z.binary("Xor", x, y)
+	for i := range z.words {
+		z.words[i] = x.words[i] ^ y.words[i]
+	}
+	return z
+}
+
+// AndNot sets z to x &^ y, the bits of x that are not set in y, and
+// returns z.
+func (z *BitArray) AndNot(x, y *BitArray) *BitArray {
+	z.binary("AndNot", x, y)
+	for i := range z.words {
+		z.words[i] = x.words[i] &^ y.words[i]
+	}
+	return z
+}
+
+// Not sets z to ^x, every bit of x flipped, and returns z.
+func (z *BitArray) Not(x *BitArray) *BitArray {
+	z.setLen(x.n)
+	for i := range z.words {
+		z.words[i] = ^x.words[i]
+	}
+	z.trim()
+	return z
+}
+
+// Shift sets z to x with every bit moved n places and returns z. A
+// positive n moves bits to higher indices, like x << n on a word; a
+// negative n moves them to lower indices, like x >> -n on an unsigned
+// word. The length does not change: bits moved past either end are lost
+// and the vacated bits are clear.
+func (z *BitArray) Shift(x *BitArray, n int) *BitArray {
+	z.setLen(x.n)
+	nw := len(z.words)
+	switch {
+	case n >= x.n || -n >= x.n:
+		clear(z.words)
+	case n > 0:
+		ws, bs := n/64, uint(n%64)
+		// Descending, so that z may be x: word i reads words i-ws and
+		// i-ws-1, which are not yet overwritten.
+		for i := nw - 1; i >= ws; i-- {
+			w := x.words[i-ws] << bs
+			if bs != 0 && i-ws-1 >= 0 {
+				w |= x.words[i-ws-1] >> (64 - bs)
+			}
+			z.words[i] = w
+		}
+		clear(z.words[:ws])
+		z.trim()
+	case n < 0:
+		ws, bs := -n/64, uint(-n%64)
+		for i := 0; i < nw-ws; i++ {
+			w := x.words[i+ws] >> bs
+			if bs != 0 && i+ws+1 < nw {
+				w |= x.words[i+ws+1] << (64 - bs)
+			}
+			z.words[i] = w
+		}
+		clear(z.words[nw-ws:])
+	default:
+		copy(z.words, x.words)
+	}
+	return z
+}
+
+// PopCount returns the number of set bits.
+func (a *BitArray) PopCount() int {
+	n := 0
+	for _, w := range a.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Any reports whether any bit is set.
+func (a *BitArray) Any() bool {
+	for _, w := range a.words {
+		if w != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,364 @@
+package bitarray
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// The tests check BitArray against a []bool doing the same work one bit
+// at a time, at lengths around word boundaries.
+var lengths = []int{0, 1, 7, 63, 64, 65, 127, 128, 200, 1000}
+
+func random(r *rand.Rand, n int) (*BitArray, []bool) {
+	a, ref := New(n), make([]bool, n)
+	for i := range n {
+		if r.IntN(3) == 0 {
+			a.Set(i)
+			ref[i] = true
+		}
+	}
+	return a, ref
+}
+
+func same(t *testing.T, what string, a *BitArray, ref []bool) {
+	t.Helper()
+	if a.Len() != len(ref) {
+		t.Fatalf("%s: Len = %d, want %d", what, a.Len(), len(ref))
+	}
+	want := make([]byte, len(ref))
+	for i, v := range ref {
+		want[i] = '0'
+		if v {
+			want[i] = '1'
+		}
+	}
+	if got := a.String(); got != string(want) {
+		t.Fatalf("%s =\n\t%s\nwant\n\t%s", what, got, want)
+	}
+	// Bits past the length must stay clear, or PopCount and Equal break.
+	if r := a.n % 64; r != 0 && a.words[len(a.words)-1]>>r != 0 {
+		t.Fatalf("%s: bits set past the length", what)
+	}
+}
+
+func TestOperators(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	ops := []struct {
+		name string
+		do   func(z, x, y *BitArray) *BitArray
+		bit  func(x, y bool) bool
+	}{
+		{"And", (*BitArray).And, func(x, y bool) bool { return x && y }},
+		{"Or", (*BitArray).Or, func(x, y bool) bool { return x || y }},
+		{"Xor", (*BitArray).Xor, func(x, y bool) bool { return x != y }},
+		{"AndNot", (*BitArray).AndNot, func(x, y bool) bool { return x && !y }},
+		{"Not", func(z, x, _ *BitArray) *BitArray { return z.Not(x) }, func(x, _ bool) bool { return !x }},
+	}
+	for _, n := range lengths {
+		for _, op := range ops {
+			x, xr := random(r, n)
+			y, yr := random(r, n)
+			want := make([]bool, n)
+			for i := range want {
+				want[i] = op.bit(xr[i], yr[i])
+			}
+			same(t, op.name, op.do(new(BitArray), x, y), want)
+			same(t, op.name+" into x", op.do(x, x, y), want)
+		}
+	}
+}
+
+func TestShift(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	for _, n := range lengths {
+		for _, s := range []int{0, 1, 3, 63, 64, 65, 100, n - 1, n, n + 1} {
+			for _, s := range []int{s, -s} {
+				x, xr := random(r, n)
+				want := make([]bool, n)
+				for i := range want {
+					if j := i - s; j >= 0 && j < n {
+						want[i] = xr[j]
+					}
+				}
+				same(t, "Shift", new(BitArray).Shift(x, s), want)
+				same(t, "Shift into x", x.Shift(x, s), want)
+			}
+		}
+	}
+}
+
+func TestRanges(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	for _, n := range lengths {
+		for range 20 {
+			a, ref := random(r, n)
+			lo := r.IntN(n + 1)
+			hi := lo + r.IntN(n-lo+1)
+			switch r.IntN(3) {
+			case 0:
+				a.SetRange(lo, hi)
+				for i := lo; i < hi; i++ {
+					ref[i] = true
+				}
+			case 1:
+				a.ClearRange(lo, hi)
+				for i := lo; i < hi; i++ {
+					ref[i] = false
+				}
+			case 2:
+				a.FlipRange(lo, hi)
+				for i := lo; i < hi; i++ {
+					ref[i] = !ref[i]
+				}
+			}
+			same(t, "range", a, ref)
+		}
+	}
+}
+
+func TestResize(t *testing.T) {
+	a := Of(100, 3, 70, 99)
+	a.Resize(71)
+	a.Resize(130)
+	if got := slices.Collect(a.Ones()); !slices.Equal(got, []int{3, 70}) {
+		t.Errorf("after shrinking and growing, Ones = %v, want [3 70]", got)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+	for _, n := range lengths {
+		a, ref := random(r, n)
+		var ones []int
+		for i, v := range ref {
+			if v {
+				ones = append(ones, i)
+			}
+		}
+		if got := a.PopCount(); got != len(ones) {
+			t.Errorf("n=%d: PopCount = %d, want %d", n, got, len(ones))
+		}
+		if got := slices.Collect(a.Ones()); !slices.Equal(got, ones) {
+			t.Errorf("n=%d: Ones = %v, want %v", n, got, ones)
+		}
+		rank := 0
+		for i := 0; i <= n; i++ {
+			if got := a.Rank(i); got != rank {
+				t.Fatalf("n=%d: Rank(%d) = %d, want %d", n, i, got, rank)
+			}
+			next, nextClear := -1, -1
+			for j := i; j < n && (next < 0 || nextClear < 0); j++ {
+				if ref[j] && next < 0 {
+					next = j
+				}
+				if !ref[j] && nextClear < 0 {
+					nextClear = j
+				}
+			}
+			if got, ok := a.NextSet(i); ok != (next >= 0) || ok && got != next {
+				t.Fatalf("n=%d: NextSet(%d) = %d, %v, want %d", n, i, got, ok, next)
+			}
+			if got, ok := a.NextClear(i); ok != (nextClear >= 0) || ok && got != nextClear {
+				t.Fatalf("n=%d: NextClear(%d) = %d, %v, want %d", n, i, got, ok, nextClear)
+			}
+			if i < n && ref[i] {
+				rank++
+			}
+		}
+		for k, want := range ones {
+			if got, ok := a.Select(k); !ok || got != want {
+				t.Fatalf("n=%d: Select(%d) = %d, %v, want %d", n, k, got, ok, want)
+			}
+		}
+		if _, ok := a.Select(len(ones)); ok {
+			t.Errorf("n=%d: Select(%d) found a bit past the last", n, len(ones))
+		}
+	}
+}
+
+func TestBinary(t *testing.T) {
+	r := rand.New(rand.NewPCG(9, 10))
+	for _, n := range lengths {
+		a, _ := random(r, n)
+		data, err := a.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := len(binary.AppendUvarint(nil, uint64(n))) + (n+7)/8; len(data) != want {
+			t.Errorf("n=%d: %d bytes, want %d", n, len(data), want)
+		}
+		var b BitArray
+		if err := b.UnmarshalBinary(data); err != nil {
+			t.Fatalf("n=%d: %v", n, err)
+		}
+		if !b.Equal(a) {
+			t.Errorf("n=%d: round trip =\n\t%s\nwant\n\t%s", n, &b, a)
+		}
+		p, err := Parse(a.String())
+		if err != nil || !p.Equal(a) {
+			t.Errorf("n=%d: Parse(String()) = %v, %v", n, p, err)
+		}
+	}
+
+	for _, data := range [][]byte{
+		{},
+		{10, 0xff},      // 10 bits in one byte
+		{3, 0xff},       // padding bits set
+		{8, 0xff, 0x00}, // a byte too many
+		{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80},
+	} {
+		var b BitArray
+		if err := b.UnmarshalBinary(data); !errors.Is(err, ErrFormat) {
+			t.Errorf("UnmarshalBinary(%x): err = %v, want ErrFormat", data, err)
+		}
+	}
+}
+
+func TestPanics(t *testing.T) {
+	for name, f := range map[string]func(){
+		"Get(-1)":        func() { New(8).Get(-1) },
+		"Set(8)":         func() { New(8).Set(8) },
+		"SetRange(4, 9)": func() { New(8).SetRange(4, 9) },
+		"And 8, 9":       func() { new(BitArray).And(New(8), New(9)) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+// Compare with:
+//
+//	go test -run '^$' -bench . -benchmem ./pkg/bitarray
+//
+// Each benchmark holds the same set, a quarter of size ids, in a
+// BitArray and in a map[int]bool.
+const size = 1 << 16
+
+var (
+	sinkInt  int
+	sinkBool bool
+)
+
+func sets(seed uint64) (*BitArray, map[int]bool) {
+	r := rand.New(rand.NewPCG(seed, seed))
+	a, m := New(size), map[int]bool{}
+	for range size / 4 {
+		i := r.IntN(size)
+		a.Set(i)
+		m[i] = true
+	}
+	return a, m
+}
+
+func BenchmarkSet(b *testing.B) {
+	b.Run("BitArray", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			a := New(size)
+			for i := 0; i < size; i += 4 {
+				a.Set(i)
+			}
+		}
+	})
+	b.Run("map", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			m := map[int]bool{}
+			for i := 0; i < size; i += 4 {
+				m[i] = true
+			}
+		}
+	})
+}
+
+func BenchmarkGet(b *testing.B) {
+	a, m := sets(1)
+	b.Run("BitArray", func(b *testing.B) {
+		for b.Loop() {
+			for i := range size {
+				sinkBool = a.Get(i)
+			}
+		}
+	})
+	b.Run("map", func(b *testing.B) {
+		for b.Loop() {
+			for i := range size {
+				sinkBool = m[i]
+			}
+		}
+	})
+}
+
+// A map keeps its length, so len is constant time, while PopCount counts
+// every word; the map pays for it on each Set instead.
+func BenchmarkPopCount(b *testing.B) {
+	a, m := sets(1)
+	b.Run("BitArray", func(b *testing.B) {
+		for b.Loop() {
+			sinkInt = a.PopCount()
+		}
+	})
+	b.Run("map", func(b *testing.B) {
+		for b.Loop() {
+			sinkInt = len(m)
+		}
+	})
+}
+
+func BenchmarkAnd(b *testing.B) {
+	a, m := sets(1)
+	a2, m2 := sets(2)
+	b.Run("BitArray", func(b *testing.B) {
+		z := New(size)
+		b.ReportAllocs()
+		for b.Loop() {
+			z.And(a, a2)
+		}
+	})
+	b.Run("map", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			z := map[int]bool{}
+			for k := range m {
+				if m2[k] {
+					z[k] = true
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkIterate(b *testing.B) {
+	a, m := sets(1)
+	b.Run("BitArray", func(b *testing.B) {
+		for b.Loop() {
+			for i := range a.Ones() {
+				sinkInt = i
+			}
+		}
+	})
+	b.Run("map", func(b *testing.B) {
+		// A map iterates in no particular order; sorting gives what Ones
+		// gives.
+		b.ReportAllocs()
+		for b.Loop() {
+			keys := make([]int, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			slices.Sort(keys)
+			for _, i := range keys {
+				sinkInt = i
+			}
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package bitarray
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrFormat is matched, via errors.Is, by every error of UnmarshalBinary
+// and Parse.
+var ErrFormat = errors.New("bitarray: invalid encoding")
+
+// MarshalBinary encodes the array as its length in bits, as a uvarint,
+// followed by the bits packed eight to a byte, bit 0 in the low bit of
+// the first byte. A million-bit array takes 125,003 bytes.
+func (a *BitArray) MarshalBinary() ([]byte, error) {
+	return a.AppendBinary(nil)
+}
+
+// AppendBinary appends the encoding of MarshalBinary to b.
+func (a *BitArray) AppendBinary(b []byte) ([]byte, error) {
+	b = binary.AppendUvarint(b, uint64(a.n))
+	nb := (a.n + 7) / 8
+	for i := range nb {
+		b = append(b, byte(a.words[i/8]>>(i%8*8)))
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes an encoding of MarshalBinary into a, replacing
+// its length and bits. Padding bits past the length must be clear.
+func (a *BitArray) UnmarshalBinary(data []byte) error {
+	n, k := binary.Uvarint(data)
+	if k <= 0 {
+		return fmt.Errorf("%w: bad length", ErrFormat)
+	}
+	data = data[k:]
+	if n > uint64(len(data))*8 || uint64(len(data)) != (n+7)/8 {
+		return fmt.Errorf("%w: %d bytes for %d bits", ErrFormat, len(data), n)
+	}
+	if r := n % 8; r != 0 && data[len(data)-1]>>r != 0 {
+		return fmt.Errorf("%w: padding bits set", ErrFormat)
+	}
+	a.setLen(int(n))
+	clear(a.words)
+	for i, c := range data {
+		a.words[i/8] |= uint64(c) << (i % 8 * 8)
+	}
+	return nil
+}
+
+// String returns the bits as 0s and 1s in index order, bit 0 first, so
+// Of(5, 0, 2) is "10100". Unlike a binary number the lowest index is on
+// the left.
+func (a *BitArray) String() string {
+	var b strings.Builder
+	b.Grow(a.n)
+	for i := range a.n {
+		b.WriteByte('0' + byte(a.words[i/64]>>(i%64)&1))
+	}
+	return b.String()
+}
+
+// Parse reads the String form: one 0 or 1 per bit, bit 0 first.
+func Parse(s string) (*BitArray, error) {
+	a := New(len(s))
+	for i := range len(s) {
+		switch s[i] {
+		case '1':
+			a.Set(i)
+		case '0':
+		default:
+			return nil, fmt.Errorf("%w: %q at offset %d of %q, want 0 or 1", ErrFormat, s[i], i, s)
+		}
+	}
+	return a, nil
+}
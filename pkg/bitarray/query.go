@@ -0,0 +1,103 @@
+package bitarray
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// NextSet returns the index of the first set bit at or after i, and
+// false if there is none. i may be anywhere from 0 to Len.
+func (a *BitArray) NextSet(i int) (int, bool) {
+	if i < 0 || i > a.n {
+		a.check(i)
+	}
+	if i == a.n {
+		return 0, false
+	}
+	wi := i / 64
+	w := a.words[wi] >> (i % 64)
+	if w != 0 {
+		return i + bits.TrailingZeros64(w), true
+	}
+	for wi++; wi < len(a.words); wi++ {
+		if w := a.words[wi]; w != 0 {
+			return wi*64 + bits.TrailingZeros64(w), true
+		}
+	}
+	return 0, false
+}
+
+// NextClear returns the index of the first clear bit at or after i, and
+// false if there is none.
+func (a *BitArray) NextClear(i int) (int, bool) {
+	if i < 0 || i > a.n {
+		a.check(i)
+	}
+	if i == a.n {
+		return 0, false
+	}
+	wi := i / 64
+	w := ^a.words[wi] >> (i % 64)
+	if w != 0 {
+		j := i + bits.TrailingZeros64(w)
+		return j, j < a.n
+	}
+	for wi++; wi < len(a.words); wi++ {
+		if w := ^a.words[wi]; w != 0 {
+			j := wi*64 + bits.TrailingZeros64(w)
+			return j, j < a.n
+		}
+	}
+	return 0, false
+}
+
+// Ones returns the indices of the set bits in increasing order:
+//
+//	for i := range a.Ones() { ... }
+func (a *BitArray) Ones() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for wi, w := range a.words {
+			for w != 0 {
+				if !yield(wi*64 + bits.TrailingZeros64(w)) {
+					return
+				}
+				w &= w - 1 // clear the lowest set bit
+			}
+		}
+	}
+}
+
+// Rank returns the number of set bits before index i, that is among bits
+// 0 through i-1. i may be anywhere from 0 to Len.
+func (a *BitArray) Rank(i int) int {
+	a.checkRange(0, i)
+	n := 0
+	for _, w := range a.words[:i/64] {
+		n += bits.OnesCount64(w)
+	}
+	if r := i % 64; r != 0 {
+		n += bits.OnesCount64(a.words[i/64] & (1<<r - 1))
+	}
+	return n
+}
+
+// Select returns the index of the set bit of rank k, the (k+1)th set bit
+// counting from 0, and false if fewer than k+1 bits are set. It inverts
+// Rank: for a set bit i, Select(Rank(i)) is i.
+func (a *BitArray) Select(k int) (int, bool) {
+	if k < 0 {
+		return 0, false
+	}
+	for wi, w := range a.words {
+		c := bits.OnesCount64(w)
+		if k >= c {
+			k -= c
+			continue
+		}
+		for ; k > 0; k-- {
+			w &= w - 1
+		}
+		return wi*64 + bits.TrailingZeros64(w), true
+	}
+	return 0, false
+}
@@ -0,0 +1,192 @@
+// Package bitview shows integer operators at work on the bits of a chosen
+// integer type. It replaces the binary forms the bitwise lessons write by
+// hand in comments, such as
+//
+//	fmt.Println("   5 & 3 =", 5&3) // 0101 & 0011 = 0001 = 1
+//
+// with aligned binary rows computed for int8 through uint64, a row of
+// carries or borrows for + and -, and every row read both as a signed
+// and as an unsigned number, so that two's complement results such as
+// ^5 == -6 are shown rather than described.
+package bitview
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Type is an integer type.
+type Type struct {
+	Name   string
+	Bits   int
+	Signed bool
+}
+
+// Types lists the integer types, int and uint as on 64-bit platforms.
+var Types = []Type{
+	{"int8", 8, true}, {"int16", 16, true}, {"int32", 32, true}, {"int64", 64, true}, {"int", 64, true},
+	{"uint8", 8, false}, {"uint16", 16, false}, {"uint32", 32, false}, {"uint64", 64, false}, {"uint", 64, false},
+}
+
+// TypeNamed returns the type called name; byte and rune are accepted as
+// the aliases of uint8 and int32.
+func TypeNamed(name string) (Type, bool) {
+	switch name {
+	case "byte":
+		name = "uint8"
+	case "rune":
+		name = "int32"
+	}
+	for _, t := range Types {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Type{}, false
+}
+
+// mask has the type's bits set.
+func (t Type) mask() uint64 {
+	return ^uint64(0) >> (64 - t.Bits)
+}
+
+// signed returns the bit pattern v read as a value of a signed type of
+// t's width.
+func (t Type) signed(v uint64) int64 {
+	return int64(v<<(64-t.Bits)) >> (64 - t.Bits)
+}
+
+// Format returns the bit pattern v as a value of t, in decimal.
+func (t Type) Format(v uint64) string {
+	if t.Signed {
+		return strconv.FormatInt(t.signed(v), 10)
+	}
+	return strconv.FormatUint(v, 10)
+}
+
+// Parse reads an integer literal as Go writes it (42, -7, 0x2a, 0b1010,
+// 0o52, 1_000) and returns its bit pattern in t. Like a constant in Go,
+// the value must fit t.
+func (t Type) Parse(s string) (uint64, error) {
+	if t.Signed {
+		n, err := strconv.ParseInt(s, 0, 64)
+		if err == nil && (n < t.signed(1<<(t.Bits-1)) || n > int64(t.mask()>>1)) {
+			err = strconv.ErrRange
+		}
+		if err != nil {
+			return 0, literalError(s, t, err)
+		}
+		return uint64(n) & t.mask(), nil
+	}
+	n, err := strconv.ParseUint(s, 0, 64)
+	if err == nil && n > t.mask() {
+		err = strconv.ErrRange
+	}
+	if err != nil {
+		return 0, literalError(s, t, err)
+	}
+	return n, nil
+}
+
+func literalError(s string, t Type, err error) error {
+	if errors.Is(err, strconv.ErrRange) || strings.HasPrefix(s, "-") {
+		return fmt.Errorf("constant %s overflows %s", s, t.Name)
+	}
+	return fmt.Errorf("invalid integer %q", s)
+}
+
+// Ops lists the operators, unary ^ written "^x".
+var Ops = []string{"&", "|", "^", "&^", "<<", ">>", "+", "-", "^x"}
+
+// Operation is one operator applied to bit patterns of a type.
+type Operation struct {
+	Type   Type
+	Op     string // one of Ops
+	X, Y   uint64 // the operands' bit patterns; Y is the count of a shift
+	Result uint64
+
+	// Carries has bit i set when bit i received a carry (for +) or a
+	// borrow (for -) from bit i-1. CarryOut is the carry or borrow out of
+	// the top bit, which Go drops.
+	Carries  uint64
+	CarryOut bool
+
+	Lost     uint64 // for <<, the bits of X shifted out, in their original positions
+	Overflow bool   // the exact result does not fit the type
+}
+
+// Apply performs op on the bit patterns x and y of type t. For shifts y
+// is the count; for unary ^ it is ignored.
+func Apply(t Type, op string, x, y uint64) (Operation, error) {
+	m := t.mask()
+	x &= m
+	if op != "<<" && op != ">>" {
+		y &= m
+	}
+	o := Operation{Type: t, Op: op, X: x, Y: y}
+	switch op {
+	case "&":
+		o.Result = x & y
+	case "|":
+		o.Result = x | y
+	case "^":
+		o.Result = x ^ y
+	case "&^":
+		o.Result = x &^ y
+	case "^x":
+		o.Y = 0
+		o.Result = ^x
+	case "<<":
+		if y >= uint64(t.Bits) {
+			o.Lost, o.Result = x, 0
+		} else {
+			o.Lost = x &^ (m >> y)
+			o.Result = x << y
+		}
+		if t.Signed {
+			o.Overflow = t.signed(o.Result&m)>>min(y, 63) != t.signed(x)
+		} else {
+			o.Overflow = o.Lost != 0
+		}
+	case ">>":
+		if t.Signed {
+			o.Result = uint64(t.signed(x) >> min(y, 63))
+		} else if y < 64 {
+			o.Result = x >> y
+		}
+	case "+":
+		sum, carry := bits.Add64(x, y, 0)
+		// Bit i of a sum is x_i ^ y_i ^ carry_i, which gives the carries.
+		o.Result, o.Carries = sum, sum^x^y
+		o.CarryOut = carry != 0
+		if t.Bits < 64 {
+			o.CarryOut = sum>>t.Bits&1 != 0
+		}
+		o.Overflow = o.CarryOut
+		if t.Signed {
+			// Operands of one sign, result of the other.
+			o.Overflow = signBit(t, ^(x^y)&(x^sum))
+		}
+	case "-":
+		diff, _ := bits.Sub64(x, y, 0)
+		o.Result, o.Carries = diff, diff^x^y
+		o.CarryOut = x < y
+		o.Overflow = o.CarryOut
+		if t.Signed {
+			// Operands of different signs, result with the sign of y.
+			o.Overflow = signBit(t, (x^y)&(x^diff))
+		}
+	default:
+		return Operation{}, fmt.Errorf("unknown operator %q; want one of %s", op, strings.Join(Ops, " "))
+	}
+	o.Result &= m
+	o.Carries &= m
+	return o, nil
+}
+
+func signBit(t Type, v uint64) bool {
+	return v>>(t.Bits-1)&1 != 0
+}
@@ -0,0 +1,132 @@
+package bitview
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestApply compares every int8 and uint8 operation with what Go computes.
+func TestApply(t *testing.T) {
+	i8, _ := TypeNamed("int8")
+	u8, _ := TypeNamed("uint8")
+	for x := range 256 {
+		for y := range 256 {
+			a, b := int8(x), int8(y)
+			ua, ub := uint8(x), uint8(y)
+			s := uint(y % 10)
+			signed := map[string]int8{"&": a & b, "|": a | b, "^": a ^ b, "&^": a &^ b, "+": a + b, "-": a - b, "^x": ^a, "<<": a << s, ">>": a >> s}
+			unsigned := map[string]uint8{"&": ua & ub, "|": ua | ub, "^": ua ^ ub, "&^": ua &^ ub, "+": ua + ub, "-": ua - ub, "^x": ^ua, "<<": ua << s, ">>": ua >> s}
+			for _, op := range Ops {
+				yv := uint64(y)
+				if op == "<<" || op == ">>" {
+					yv = uint64(s)
+				}
+				o, err := Apply(i8, op, uint64(x), yv)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := int8(o.Result); got != signed[op] {
+					t.Fatalf("int8: %s = %d, want %d", o.Expr(), got, signed[op])
+				}
+				if want, ok := exactInt8(op, a, b, s); ok && o.Overflow != (want != int(signed[op])) {
+					t.Fatalf("int8: %s: Overflow = %v, exact result %d", o.Expr(), o.Overflow, want)
+				}
+				o, err = Apply(u8, op, uint64(x), yv)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := uint8(o.Result); got != unsigned[op] {
+					t.Fatalf("uint8: %s = %d, want %d", o.Expr(), got, unsigned[op])
+				}
+			}
+		}
+	}
+}
+
+// exactInt8 returns the mathematical result of an int8 +, - or <<, the
+// operations that can overflow.
+func exactInt8(op string, a, b int8, s uint) (int, bool) {
+	switch op {
+	case "+":
+		return int(a) + int(b), true
+	case "-":
+		return int(a) - int(b), true
+	case "<<":
+		return int(a) << s, true
+	}
+	return 0, false
+}
+
+func TestParse(t *testing.T) {
+	i8, _ := TypeNamed("int8")
+	tests := []struct {
+		s    string
+		want uint64
+		err  string
+	}{
+		{"-1", 0xff, ""},
+		{"0x7f", 0x7f, ""},
+		{"-0b1000_0000", 0x80, ""},
+		{"128", 0, "constant 128 overflows int8"},
+		{"-129", 0, "constant -129 overflows int8"},
+		{"five", 0, `invalid integer "five"`},
+	}
+	for _, tt := range tests {
+		got, err := i8.Parse(tt.s)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("Parse(%q): err = %v, want %s", tt.s, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %#x, %v, want %#x", tt.s, got, err, tt.want)
+		}
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	i8, _ := TypeNamed("int8")
+	o, err := Apply(i8, "+", 127, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := o.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `int8: 127 + 1
+
+                       hex  signed  unsigned
+  carry   1111 111
+      x   0111 1111   0x7f     127       127
+    + y   0000 0001   0x01       1         1
+          ─────────
+      =   1000 0000   0x80    -128       128
+
+  * overflow: 127 + 1 is 128 exactly, which does not fit int8; Go keeps the low 8 bits, -128
+  * as int8 the top bit counts -2^7, so 1000 0000 = -128 + 0 = -128
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteText:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestShiftNote(t *testing.T) {
+	for _, tt := range []struct {
+		typ, want string
+	}{
+		{"uint8", "shifting an 8-bit value by 8 or more leaves 0"},
+		{"int16", "shifting a 16-bit value by 16 or more leaves 0"},
+	} {
+		typ, _ := TypeNamed(tt.typ)
+		o, err := Apply(typ, "<<", 1, uint64(typ.Bits))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if notes := o.Notes(); !slices.Contains(notes, tt.want) {
+			t.Errorf("%s: notes %q, want %q", tt.typ, notes, tt.want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package bitview
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Binary writes the low bits of v in groups of four, most significant
+// first: "0000 0101".
+func Binary(v uint64, width int) string {
+	return marks(width, func(i int) byte { return '0' + byte(v>>i&1) })
+}
+
+// marks writes one character per bit, most significant first, in groups
+// of four.
+func marks(width int, char func(bit int) byte) string {
+	var b strings.Builder
+	for i := width - 1; i >= 0; i-- {
+		b.WriteByte(char(i))
+		if i > 0 && i%4 == 0 {
+			b.WriteByte(' ')
+		}
+	}
+	return b.String()
+}
+
+// Expr returns the operation as Go source, e.g. "5 & 3" or "^5".
+func (o Operation) Expr() string {
+	x := o.Type.Format(o.X)
+	switch o.Op {
+	case "^x":
+		return "^" + x
+	case "<<", ">>":
+		return fmt.Sprintf("%s %s %d", x, o.Op, o.Y)
+	}
+	return fmt.Sprintf("%s %s %s", x, o.Op, o.Type.Format(o.Y))
+}
+
+// WriteText writes the operation as written arithmetic: the operands and
+// the result in aligned binary rows with their hex, signed and unsigned
+// readings, the carries or borrows of + and -, the bits << shifts out,
+// and notes on what happened.
+func (o Operation) WriteText(w io.Writer) error {
+	t := o.Type
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s: %s\n\n", t.Name, o.Expr())
+
+	type row struct{ label, bits, hex, signed, unsigned string }
+	value := func(label string, v uint64) row {
+		hexDigits := t.Bits / 4
+		return row{label, "  " + Binary(v, t.Bits), fmt.Sprintf("0x%0*x", hexDigits, v),
+			strconv.FormatInt(t.signed(v), 10), strconv.FormatUint(v, 10)}
+	}
+	rows := []row{{"", "", "hex", "signed", "unsigned"}}
+	switch o.Op {
+	case "+", "-":
+		label, out := "carry", "  "
+		if o.Op == "-" {
+			label = "borrow"
+		}
+		if o.CarryOut {
+			out = "1 "
+		}
+		rows = append(rows, row{label, out + marks(t.Bits, func(i int) byte {
+			if o.Carries>>i&1 != 0 {
+				return '1'
+			}
+			return ' '
+		}), "", "", ""})
+	}
+	rows = append(rows, value("x", o.X))
+	switch o.Op {
+	case "^x":
+		rows[len(rows)-1].label = "^ x"
+	case "<<", ">>":
+		rows = append(rows, row{o.Op + " " + strconv.FormatUint(o.Y, 10), "", "", "", ""})
+	default:
+		rows = append(rows, value(o.Op+" y", o.Y))
+	}
+	if o.Op == "<<" && o.Lost != 0 {
+		rows = append(rows, row{"out", "  " + marks(t.Bits, func(i int) byte {
+			if o.Lost>>i&1 != 0 {
+				return '0' + byte(o.X>>i&1)
+			}
+			return ' '
+		}), "", "", ""})
+	}
+	rows = append(rows, row{"", "  " + strings.Repeat("─", len(Binary(0, t.Bits))), "", "", ""})
+	rows = append(rows, value("=", o.Result))
+
+	var lw, bitsW, hw, sw, uw int
+	for _, r := range rows {
+		lw = max(lw, len(r.label))
+		bitsW = max(bitsW, len([]rune(r.bits)))
+		hw, sw, uw = max(hw, len(r.hex)), max(sw, len(r.signed)), max(uw, len(r.unsigned))
+	}
+	for _, r := range rows {
+		pad := bitsW - len([]rune(r.bits))
+		line := fmt.Sprintf("  %*s %s%s   %*s  %*s  %*s", lw, r.label, r.bits, strings.Repeat(" ", pad), hw, r.hex, sw, r.signed, uw, r.unsigned)
+		fmt.Fprintln(bw, strings.TrimRight(line, " "))
+	}
+
+	if notes := o.Notes(); len(notes) > 0 {
+		fmt.Fprintln(bw)
+		for _, n := range notes {
+			fmt.Fprintf(bw, "  * %s\n", n)
+		}
+	}
+	return bw.Flush()
+}
+
+// Notes explains what the operation did to the bits that the rows alone
+// do not say.
+func (o Operation) Notes() []string {
+	t := o.Type
+	var notes []string
+	switch o.Op {
+	case "^x":
+		if t.Signed {
+			notes = append(notes, fmt.Sprintf("^x flips every bit; in two's complement that is -x-1, so %s = %s", o.Expr(), t.Format(o.Result)))
+		} else {
+			notes = append(notes, fmt.Sprintf("^x flips every bit; in %s that is %d - x, so %s = %s", t.Name, t.mask(), o.Expr(), t.Format(o.Result)))
+		}
+	case "&^":
+		notes = append(notes, "x &^ y (AND NOT) clears in x every bit that is set in y")
+	case "<<":
+		if o.Lost != 0 {
+			notes = append(notes, fmt.Sprintf("%d set bit(s) were shifted out of the top and are lost", bits.OnesCount64(o.Lost)))
+		}
+		if o.Y >= uint64(t.Bits) {
+			article := "a"
+			if t.Bits == 8 {
+				article = "an"
+			}
+			notes = append(notes, fmt.Sprintf("shifting %s %d-bit value by %d or more leaves 0", article, t.Bits, t.Bits))
+		} else if o.Y > 0 && !o.Overflow && o.X != 0 {
+			notes = append(notes, fmt.Sprintf("x << %d multiplies by 2^%d = %d", o.Y, o.Y, uint64(1)<<o.Y))
+		}
+	case ">>":
+		switch {
+		case t.Signed && signBit(t, o.X):
+			notes = append(notes, "x is negative, so >> copies the sign bit 1 into the vacated bits (arithmetic shift); the result rounds toward minus infinity")
+		case o.Y > 0:
+			notes = append(notes, fmt.Sprintf("zeros are shifted in; x >> %d divides by 2^%d, dropping the remainder", o.Y, o.Y))
+		}
+	case "+", "-":
+		if o.CarryOut {
+			what := "carry"
+			if o.Op == "-" {
+				what = "borrow"
+			}
+			notes = append(notes, fmt.Sprintf("the %s out of bit %d is dropped", what, t.Bits-1))
+		}
+	}
+	if o.Overflow && (o.Op == "+" || o.Op == "-" || o.Op == "<<") {
+		notes = append(notes, fmt.Sprintf("overflow: %s is %s exactly, which does not fit %s; Go keeps the low %d bits, %s", o.Expr(), o.exact(), t.Name, t.Bits, t.Format(o.Result)))
+	}
+	if t.Signed && signBit(t, o.Result) {
+		low := o.Result &^ (1 << (t.Bits - 1))
+		notes = append(notes, fmt.Sprintf("as %s the top bit counts -2^%d, so %s = %s + %d = %s",
+			t.Name, t.Bits-1, Binary(o.Result, t.Bits), t.Format(1<<(t.Bits-1)), low, t.Format(o.Result)))
+	}
+	return notes
+}
+
+// exact returns the mathematical result of +, - or <<.
+func (o Operation) exact() string {
+	t := o.Type
+	read := func(v uint64) *big.Int {
+		if t.Signed {
+			return big.NewInt(t.signed(v))
+		}
+		return new(big.Int).SetUint64(v)
+	}
+	x, y := read(o.X), read(o.Y)
+	switch o.Op {
+	case "+":
+		return x.Add(x, y).String()
+	case "-":
+		return x.Sub(x, y).String()
+	}
+	return x.Lsh(x, uint(min(o.Y, 1024))).String()
+}
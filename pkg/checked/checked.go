@@ -0,0 +1,229 @@
+// Package checked does integer arithmetic that does not overflow silently.
+//
+// Go integer arithmetic wraps: an int8 holding 127 plus 1 is -128 (see
+// Codes/Day4/04_minmax). For every operation this package offers three
+// behaviours, so code can choose explicitly:
+//
+//	Add, Sub, Mul, Div, Neg                return an error wrapping ErrOverflow
+//	SaturatingAdd, SaturatingSub, ...      clamp to the type's min or max
+//	WrappingAdd, WrappingSub, ...          wrap like plain Go arithmetic
+//
+// All functions are generic over every integer type, including named types
+// such as type Cents int64.
+package checked
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// Integer is any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+var (
+	// ErrOverflow is matched by every *Error, via errors.Is.
+	ErrOverflow = errors.New("integer overflow")
+
+	// ErrDivideByZero is returned by Div for a zero divisor.
+	ErrDivideByZero = errors.New("integer divide by zero")
+)
+
+// Error reports an operation whose exact result does not fit its type.
+type Error struct {
+	Op   string // "+", "-", "*", "/" or "-x" for negation
+	Type string // the operand type, e.g. "int8" or "billing.Cents"
+	X, Y string // operands in decimal; Y is empty for negation
+}
+
+func (e *Error) Error() string {
+	if e.Op == "-x" {
+		return fmt.Sprintf("checked: %s overflow: -(%s)", e.Type, e.X)
+	}
+	return fmt.Sprintf("checked: %s overflow: %s %s %s", e.Type, e.X, e.Op, e.Y)
+}
+
+// Is reports whether target is ErrOverflow.
+func (e *Error) Is(target error) bool {
+	return target == ErrOverflow
+}
+
+func overflow[T Integer](op string, x, y T) error {
+	e := &Error{Op: op, Type: fmt.Sprintf("%T", x), X: fmt.Sprintf("%d", x)}
+	if op != "-x" {
+		e.Y = fmt.Sprintf("%d", y)
+	}
+	return e
+}
+
+// signed reports whether T is a signed integer type.
+func signed[T Integer]() bool {
+	return ^T(0) < 0
+}
+
+// Max returns the largest value of T.
+func Max[T Integer]() T {
+	var zero T
+	if signed[T]() {
+		return T(1)<<(unsafe.Sizeof(zero)*8-1) - 1
+	}
+	return ^zero
+}
+
+// Min returns the smallest value of T.
+func Min[T Integer]() T {
+	if signed[T]() {
+		return ^Max[T]()
+	}
+	return 0
+}
+
+// Add returns x + y. If the sum overflows it returns the wrapped sum and an
+// *Error.
+func Add[T Integer](x, y T) (T, error) {
+	s := x + y
+	if (s > x) != (y > 0) {
+		return s, overflow("+", x, y)
+	}
+	return s, nil
+}
+
+// Sub returns x - y. If the difference overflows it returns the wrapped
+// difference and an *Error.
+func Sub[T Integer](x, y T) (T, error) {
+	d := x - y
+	if (d < x) != (y > 0) {
+		return d, overflow("-", x, y)
+	}
+	return d, nil
+}
+
+// Mul returns x * y. If the product overflows it returns the wrapped
+// product and an *Error.
+func Mul[T Integer](x, y T) (T, error) {
+	if mulOverflows(x, y) {
+		return x * y, overflow("*", x, y)
+	}
+	return x * y, nil
+}
+
+func mulOverflows[T Integer](x, y T) bool {
+	if x == 0 || y == 0 {
+		return false
+	}
+	minusOne := ^T(0) // -1 when T is signed
+	if signed[T]() && (x == minusOne && y == Min[T]() || y == minusOne && x == Min[T]()) {
+		return true // -1 * MinT wraps to MinT, and MinT / -1 is MinT again
+	}
+	return (x*y)/y != x
+}
+
+// Div returns x / y truncated towards zero, like Go's /. It returns
+// ErrDivideByZero if y is zero, and an *Error for MinT / -1, whose result
+// is one more than MaxT.
+func Div[T Integer](x, y T) (T, error) {
+	if y == 0 {
+		return 0, ErrDivideByZero
+	}
+	if divOverflows(x, y) {
+		return x, overflow("/", x, y)
+	}
+	return x / y, nil
+}
+
+func divOverflows[T Integer](x, y T) bool {
+	return signed[T]() && x == Min[T]() && y == ^T(0)
+}
+
+// Neg returns -x. Negating MinT overflows for signed types, and negating
+// anything but zero overflows for unsigned ones; both return the wrapped
+// value and an *Error.
+func Neg[T Integer](x T) (T, error) {
+	if negOverflows(x) {
+		return -x, overflow("-x", x, 0)
+	}
+	return -x, nil
+}
+
+func negOverflows[T Integer](x T) bool {
+	if signed[T]() {
+		return x == Min[T]()
+	}
+	return x != 0
+}
+
+// SaturatingAdd returns x + y clamped to [MinT, MaxT].
+func SaturatingAdd[T Integer](x, y T) T {
+	s, err := Add(x, y)
+	if err == nil {
+		return s
+	}
+	if y > 0 {
+		return Max[T]()
+	}
+	return Min[T]()
+}
+
+// SaturatingSub returns x - y clamped to [MinT, MaxT].
+func SaturatingSub[T Integer](x, y T) T {
+	d, err := Sub(x, y)
+	if err == nil {
+		return d
+	}
+	if y > 0 {
+		return Min[T]()
+	}
+	return Max[T]()
+}
+
+// SaturatingMul returns x * y clamped to [MinT, MaxT].
+func SaturatingMul[T Integer](x, y T) T {
+	if !mulOverflows(x, y) {
+		return x * y
+	}
+	if (x < 0) != (y < 0) {
+		return Min[T]()
+	}
+	return Max[T]()
+}
+
+// SaturatingDiv returns x / y clamped to [MinT, MaxT]: MinT / -1 is MaxT.
+// Like Go's /, it panics if y is zero.
+func SaturatingDiv[T Integer](x, y T) T {
+	if divOverflows(x, y) {
+		return Max[T]()
+	}
+	return x / y
+}
+
+// SaturatingNeg returns -x clamped to [MinT, MaxT]: -MinT is MaxT, and the
+// negation of any unsigned value is 0.
+func SaturatingNeg[T Integer](x T) T {
+	if !negOverflows(x) {
+		return -x
+	}
+	if signed[T]() {
+		return Max[T]()
+	}
+	return 0
+}
+
+// WrappingAdd returns x + y modulo 2^bits, exactly like Go's +. It exists
+// so that code relying on wrap-around can say so.
+func WrappingAdd[T Integer](x, y T) T { return x + y }
+
+// WrappingSub returns x - y modulo 2^bits, like Go's -.
+func WrappingSub[T Integer](x, y T) T { return x - y }
+
+// WrappingMul returns x * y modulo 2^bits, like Go's *.
+func WrappingMul[T Integer](x, y T) T { return x * y }
+
+// WrappingDiv returns x / y, like Go's /: MinT / -1 wraps to MinT. It
+// panics if y is zero.
+func WrappingDiv[T Integer](x, y T) T { return x / y }
+
+// WrappingNeg returns -x modulo 2^bits, like Go's unary -.
+func WrappingNeg[T Integer](x T) T { return -x }
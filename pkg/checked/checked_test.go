@@ -0,0 +1,227 @@
+package checked
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"unsafe"
+)
+
+// Every operation is compared with the exact result computed in big.Int:
+// the checked form must fail exactly when that result is out of range,
+// the wrapping form must equal it modulo 2^bits and the saturating form
+// must equal it clamped to [MinT, MaxT]. 8-bit types are tested on every
+// pair of values, wider types on the values around each boundary.
+
+type cents int64 // a named type, as billing code would use
+
+func TestInt8(t *testing.T)    { testAll(t, every[int8]()) }
+func TestUint8(t *testing.T)   { testAll(t, every[uint8]()) }
+func TestInt16(t *testing.T)   { testAll(t, boundaries[int16]()) }
+func TestUint16(t *testing.T)  { testAll(t, boundaries[uint16]()) }
+func TestInt32(t *testing.T)   { testAll(t, boundaries[int32]()) }
+func TestUint32(t *testing.T)  { testAll(t, boundaries[uint32]()) }
+func TestInt64(t *testing.T)   { testAll(t, boundaries[int64]()) }
+func TestUint64(t *testing.T)  { testAll(t, boundaries[uint64]()) }
+func TestInt(t *testing.T)     { testAll(t, boundaries[int]()) }
+func TestUint(t *testing.T)    { testAll(t, boundaries[uint]()) }
+func TestUintptr(t *testing.T) { testAll(t, boundaries[uintptr]()) }
+func TestNamed(t *testing.T)   { testAll(t, boundaries[cents]()) }
+
+func TestLimits(t *testing.T) {
+	limits := []struct {
+		name     string
+		min, max *big.Int
+		wantMin  int64
+		wantMax  uint64
+	}{
+		{"int8", toBig(Min[int8]()), toBig(Max[int8]()), math.MinInt8, math.MaxInt8},
+		{"int16", toBig(Min[int16]()), toBig(Max[int16]()), math.MinInt16, math.MaxInt16},
+		{"int32", toBig(Min[int32]()), toBig(Max[int32]()), math.MinInt32, math.MaxInt32},
+		{"int64", toBig(Min[int64]()), toBig(Max[int64]()), math.MinInt64, math.MaxInt64},
+		{"int", toBig(Min[int]()), toBig(Max[int]()), math.MinInt, math.MaxInt},
+		{"uint8", toBig(Min[uint8]()), toBig(Max[uint8]()), 0, math.MaxUint8},
+		{"uint16", toBig(Min[uint16]()), toBig(Max[uint16]()), 0, math.MaxUint16},
+		{"uint32", toBig(Min[uint32]()), toBig(Max[uint32]()), 0, math.MaxUint32},
+		{"uint64", toBig(Min[uint64]()), toBig(Max[uint64]()), 0, math.MaxUint64},
+		{"uint", toBig(Min[uint]()), toBig(Max[uint]()), 0, math.MaxUint},
+	}
+	for _, l := range limits {
+		if l.min.Int64() != l.wantMin || l.max.Uint64() != l.wantMax {
+			t.Errorf("%s: Min, Max = %v, %v; want %d, %d", l.name, l.min, l.max, l.wantMin, l.wantMax)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	_, err := Add[int8](math.MaxInt8, 1)
+	if !errors.Is(err, ErrOverflow) {
+		t.Fatalf("Add(127, 1): err = %v, want ErrOverflow", err)
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Op != "+" || e.Type != "int8" || e.X != "127" || e.Y != "1" {
+		t.Errorf("Add(127, 1): err = %#v", err)
+	}
+	if got, want := err.Error(), "checked: int8 overflow: 127 + 1"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	_, err = Neg(cents(math.MinInt64))
+	if got, want := err.Error(), "checked: checked.cents overflow: -(-9223372036854775808)"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	if _, err := Div[uint8](1, 0); err != ErrDivideByZero {
+		t.Errorf("Div(1, 0): err = %v, want ErrDivideByZero", err)
+	}
+	if errors.Is(ErrDivideByZero, ErrOverflow) {
+		t.Error("ErrDivideByZero matches ErrOverflow")
+	}
+}
+
+// every returns every value of an 8-bit type.
+func every[T int8 | uint8]() []T {
+	var values []T
+	for v := Min[T](); ; v++ {
+		values = append(values, v)
+		if v == Max[T]() {
+			return values
+		}
+	}
+}
+
+// boundaries returns values at and around zero, MinT, MaxT and the square
+// root of MaxT, where multiplication starts to overflow.
+func boundaries[T Integer]() []T {
+	var zero T
+	half := T(1) << (unsafe.Sizeof(zero) * 4) // 2^(bits/2)
+	min, max := Min[T](), Max[T]()
+	candidates := []T{
+		0, 1, 2, 3,
+		max, max - 1, max - 2, max / 2, max/2 + 1, max/2 - 1,
+		half, half - 1, half + 1, half / 2, half/2 - 1, half/2 + 1,
+		min, min + 1, min + 2, min / 2, min/2 + 1, min/2 - 1,
+	}
+	if signed[T]() {
+		for _, c := range []T{1, 2, 3, half, half - 1, half + 1, half / 2, half/2 - 1, half/2 + 1} {
+			candidates = append(candidates, WrappingNeg(c))
+		}
+	}
+	seen := map[T]bool{}
+	var values []T
+	for _, c := range candidates {
+		if !seen[c] {
+			seen[c] = true
+			values = append(values, c)
+		}
+	}
+	return values
+}
+
+func toBig[T Integer](x T) *big.Int {
+	if signed[T]() {
+		return big.NewInt(int64(x))
+	}
+	return new(big.Int).SetUint64(uint64(x))
+}
+
+// wrap reduces exact modulo 2^bits, as Go arithmetic on T does.
+func wrap[T Integer](exact *big.Int) T {
+	var zero T
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(unsafe.Sizeof(zero)*8))
+	mask.Sub(mask, big.NewInt(1))
+	return T(new(big.Int).And(exact, mask).Uint64())
+}
+
+// clamp returns exact limited to [MinT, MaxT].
+func clamp[T Integer](exact *big.Int) T {
+	switch {
+	case exact.Cmp(toBig(Max[T]())) > 0:
+		return Max[T]()
+	case exact.Cmp(toBig(Min[T]())) < 0:
+		return Min[T]()
+	}
+	return wrap[T](exact)
+}
+
+func fits[T Integer](exact *big.Int) bool {
+	return exact.Cmp(toBig(Min[T]())) >= 0 && exact.Cmp(toBig(Max[T]())) <= 0
+}
+
+type binary[T Integer] struct {
+	name       string
+	exact      func(z, x, y *big.Int) *big.Int
+	checked    func(x, y T) (T, error)
+	saturating func(x, y T) T
+	wrapping   func(x, y T) T
+}
+
+func testAll[T Integer](t *testing.T, values []T) {
+	ops := []binary[T]{
+		{"Add", (*big.Int).Add, Add[T], SaturatingAdd[T], WrappingAdd[T]},
+		{"Sub", (*big.Int).Sub, Sub[T], SaturatingSub[T], WrappingSub[T]},
+		{"Mul", (*big.Int).Mul, Mul[T], SaturatingMul[T], WrappingMul[T]},
+		{"Div", (*big.Int).Quo, Div[T], SaturatingDiv[T], WrappingDiv[T]},
+	}
+	for _, op := range ops {
+		failures := 0
+		for _, x := range values {
+			for _, y := range values {
+				if op.name == "Div" && y == 0 {
+					if _, err := Div(x, y); err != ErrDivideByZero {
+						t.Errorf("Div(%d, 0): err = %v, want ErrDivideByZero", x, err)
+					}
+					continue
+				}
+				exact := op.exact(new(big.Int), toBig(x), toBig(y))
+				if !checkBinary(t, op, x, y, exact) {
+					failures++
+				}
+				if failures >= 10 {
+					t.Fatalf("%s: too many failures", op.name)
+				}
+			}
+		}
+	}
+
+	for _, x := range values {
+		exact := new(big.Int).Neg(toBig(x))
+		got, err := Neg(x)
+		if want := wrap[T](exact); got != want {
+			t.Errorf("Neg(%d) = %d, want %d", x, got, want)
+		}
+		if (err == nil) != fits[T](exact) {
+			t.Errorf("Neg(%d): err = %v, exact result %v", x, err, exact)
+		}
+		if got, want := SaturatingNeg(x), clamp[T](exact); got != want {
+			t.Errorf("SaturatingNeg(%d) = %d, want %d", x, got, want)
+		}
+		if got, want := WrappingNeg(x), wrap[T](exact); got != want {
+			t.Errorf("WrappingNeg(%d) = %d, want %d", x, got, want)
+		}
+	}
+}
+
+func checkBinary[T Integer](t *testing.T, op binary[T], x, y T, exact *big.Int) bool {
+	t.Helper()
+	ok := true
+	got, err := op.checked(x, y)
+	if want := wrap[T](exact); got != want {
+		t.Errorf("%s(%d, %d) = %d, want %d", op.name, x, y, got, want)
+		ok = false
+	}
+	if overflowed := !fits[T](exact); (err != nil) != overflowed || err != nil && !errors.Is(err, ErrOverflow) {
+		t.Errorf("%s(%d, %d): err = %v, exact result %v", op.name, x, y, err, exact)
+		ok = false
+	}
+	if got, want := op.saturating(x, y), clamp[T](exact); got != want {
+		t.Errorf("Saturating%s(%d, %d) = %d, want %d", op.name, x, y, got, want)
+		ok = false
+	}
+	if got, want := op.wrapping(x, y), wrap[T](exact); got != want {
+		t.Errorf("Wrapping%s(%d, %d) = %d, want %d", op.name, x, y, got, want)
+		ok = false
+	}
+	return ok
+}
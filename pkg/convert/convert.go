@@ -0,0 +1,299 @@
+// Package convert converts between numeric types without losing
+// information silently.
+//
+// A Go conversion never fails: int(25.5) is 25, uint8(300) is 44,
+// uint(-1) is the largest uint and float32(0.1) is a little more than
+// 0.1 (see Codes/Day7/01_ArithmeticOperators). Convert returns an error
+// instead whenever the result would not equal the value:
+//
+//	convert.Convert[int](25.5)       // 0, fractional part 0.5 would be lost
+//	convert.Convert[uint8](300)      // 0, out of range
+//	convert.Convert[int](50.0)       // 50, nil
+//
+// Round is for code that means to round: it converts with an explicit
+// Mode (Truncate, Floor, Ceil or HalfEven) and fails only for values the
+// mode rounds out of range, NaN and infinities. Rounding toward zero
+// brings any finite float into a float type's range, so
+// Round[float32](1e39, Floor) is math.MaxFloat32.
+//
+// Both are generic over every integer and floating-point type, including
+// named types such as type Celsius float64; the error names the types.
+package convert
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// Number is any integer or floating-point type.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// The reasons a conversion fails. An *Error matches exactly one of them,
+// via errors.Is.
+var (
+	ErrTruncated = errors.New("fractional part would be lost")
+	ErrInexact   = errors.New("not exactly representable")
+	ErrOverflow  = errors.New("out of range")
+	ErrSignLoss  = errors.New("negative value to unsigned type")
+	ErrNaN       = errors.New("NaN has no integer value")
+	ErrInf       = errors.New("infinity has no integer value")
+)
+
+// Error reports a conversion that would lose information.
+type Error struct {
+	Value    string // the value in its source type, e.g. "25.5"
+	From, To string // type names, e.g. "float64" and "int"
+	Err      error  // one of ErrTruncated, ErrInexact, ErrOverflow, ErrSignLoss, ErrNaN and ErrInf
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("convert: %s %s to %s: %v", e.From, e.Value, e.To, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Mode is a rounding mode.
+type Mode int
+
+const (
+	Truncate Mode = iota // toward zero, as Go's float-to-integer conversion
+	Floor                // toward minus infinity
+	Ceil                 // toward plus infinity
+	HalfEven             // to the nearest, ties to even, as Go's float conversions
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Truncate:
+		return "Truncate"
+	case Floor:
+		return "Floor"
+	case Ceil:
+		return "Ceil"
+	case HalfEven:
+		return "HalfEven"
+	}
+	return "Mode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// exact is the internal mode of Convert: no rounding allowed.
+const exact Mode = -1
+
+// Convert returns v as a To if To holds exactly the same value, and an
+// *Error otherwise. Integers convert to integers of any width when in
+// range; floats convert to integers when they have no fractional part;
+// integers and floats convert to floats when the value needs no rounding,
+// so Convert[float64](1<<53 + 1) fails. NaN and infinities convert only
+// between float types.
+func Convert[To, From Number](v From) (To, error) {
+	return convert[To](v, exact)
+}
+
+// Round returns v as a To, rounded with mode when To cannot hold it
+// exactly: a fractional part when To is an integer type, precision when To
+// is a float type. It returns an *Error for values outside To's range
+// (ErrOverflow or ErrSignLoss), and for NaN and infinities when To is an
+// integer type.
+func Round[To, From Number](v From, mode Mode) (To, error) {
+	if mode < Truncate || mode > HalfEven {
+		panic(fmt.Sprintf("convert: invalid rounding mode %v", mode))
+	}
+	return convert[To](v, mode)
+}
+
+// kind classifies a Number type.
+type kind int
+
+const (
+	signedKind kind = iota
+	unsignedKind
+	floatKind
+)
+
+// info describes the type T: its kind and size in bits.
+func info[T Number]() (kind, int) {
+	t := reflect.TypeFor[T]()
+	bits := t.Bits()
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return floatKind, bits
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return unsignedKind, bits
+	}
+	return signedKind, bits
+}
+
+// value returns v exactly as a big.Float, and its decimal form. NaN has
+// no big.Float and is returned as nil.
+func value[T Number](v T) (*big.Float, string) {
+	switch k, bits := info[T](); k {
+	case signedKind:
+		return new(big.Float).SetInt64(int64(v)), strconv.FormatInt(int64(v), 10)
+	case unsignedKind:
+		return new(big.Float).SetUint64(uint64(v)), strconv.FormatUint(uint64(v), 10)
+	default:
+		f := float64(v)
+		s := strconv.FormatFloat(f, 'g', -1, bits)
+		if math.IsNaN(f) {
+			return nil, s
+		}
+		return new(big.Float).SetFloat64(f), s
+	}
+}
+
+func convert[To Number, From Number](v From, mode Mode) (To, error) {
+	x, s := value(v)
+	fail := func(err error) (To, error) {
+		return 0, &Error{Value: s, From: reflect.TypeFor[From]().String(), To: reflect.TypeFor[To]().String(), Err: err}
+	}
+	toKind, toBits := info[To]()
+
+	if toKind == floatKind {
+		if x == nil {
+			return To(math.NaN()), nil
+		}
+		f, err := toFloat(x, toBits, mode)
+		if err != nil {
+			return fail(err)
+		}
+		return To(f), nil
+	}
+
+	switch {
+	case x == nil:
+		return fail(ErrNaN)
+	case x.IsInf():
+		return fail(ErrInf)
+	}
+	i, err := toInt(x, mode)
+	if rangeErr := intRange(i, toKind, toBits); rangeErr != nil {
+		err = rangeErr
+	}
+	if err != nil {
+		return fail(err)
+	}
+	if toKind == unsignedKind {
+		return To(i.Uint64()), nil
+	}
+	return To(i.Int64()), nil
+}
+
+// toInt rounds x to an integer with mode, failing with ErrTruncated for
+// the exact mode if x is not an integer. The caller checks the range
+// first, so that -1.5 to a uint is a sign loss rather than a truncation.
+func toInt(x *big.Float, mode Mode) (*big.Int, error) {
+	i, acc := x.Int(nil) // truncated toward zero
+	if acc == big.Exact {
+		return i, nil
+	}
+	switch mode {
+	case exact:
+		if x.Sign() < 0 && i.Sign() == 0 {
+			// -0.5 truncates to 0, which an unsigned type holds, but the
+			// value was negative.
+			i.SetInt64(-1)
+		}
+		return i, ErrTruncated
+	case Floor:
+		if x.Sign() < 0 {
+			i.Sub(i, big.NewInt(1))
+		}
+	case Ceil:
+		if x.Sign() > 0 {
+			i.Add(i, big.NewInt(1))
+		}
+	case HalfEven:
+		frac := new(big.Float).Sub(x, new(big.Float).SetInt(i))
+		half := frac.Abs(frac).Cmp(big.NewFloat(0.5))
+		if half > 0 || half == 0 && i.Bit(0) == 1 {
+			i.Add(i, big.NewInt(int64(x.Sign())))
+		}
+	}
+	return i, nil
+}
+
+// intRange reports whether i fits an integer type of the kind and size.
+func intRange(i *big.Int, k kind, bits int) error {
+	if k == unsignedKind {
+		switch {
+		case i.Sign() < 0:
+			return ErrSignLoss
+		case i.BitLen() > bits:
+			return ErrOverflow
+		}
+		return nil
+	}
+	lo := new(big.Int).Lsh(big.NewInt(-1), uint(bits-1))
+	hi := new(big.Int).Sub(new(big.Int).Neg(lo), big.NewInt(1))
+	if i.Cmp(lo) < 0 || i.Cmp(hi) > 0 {
+		return ErrOverflow
+	}
+	return nil
+}
+
+// toFloat rounds x to a float of the size with mode, failing with
+// ErrInexact for the exact mode when x needs rounding, and with
+// ErrOverflow when x is finite but rounds to an infinity: beyond the
+// largest finite float for the exact mode, past it for Ceil or Floor, or
+// past the halfway point to the next power of two for HalfEven, as Go's
+// conversion rounds. Truncate never overflows. Values too small for the
+// type round to zero or the smallest denormal, as the mode says.
+func toFloat(x *big.Float, bits int, mode Mode) (float64, error) {
+	if x.IsInf() {
+		f, _ := x.Float64()
+		return f, nil
+	}
+	if mode == exact {
+		largest := math.MaxFloat64
+		if bits == 32 {
+			largest = math.MaxFloat32
+		}
+		if new(big.Float).Abs(x).Cmp(big.NewFloat(largest)) > 0 {
+			return 0, ErrOverflow
+		}
+	}
+
+	var f float64
+	var acc big.Accuracy
+	// next steps f one float of the target size toward dir.
+	var next func(f, dir float64) float64
+	if bits == 32 {
+		f32, a := x.Float32() // nearest, ties to even
+		f, acc = float64(f32), a
+		next = func(f, dir float64) float64 { return float64(math.Nextafter32(float32(f), float32(dir))) }
+	} else {
+		f, acc = x.Float64()
+		next = math.Nextafter
+	}
+	if acc == big.Exact {
+		return f, nil
+	}
+	switch mode {
+	case exact:
+		return 0, ErrInexact
+	case Floor:
+		if acc == big.Above {
+			f = next(f, math.Inf(-1))
+		}
+	case Ceil:
+		if acc == big.Below {
+			f = next(f, math.Inf(1))
+		}
+	case Truncate:
+		if acc == big.Above && x.Sign() > 0 || acc == big.Below && x.Sign() < 0 {
+			f = next(f, 0)
+		}
+	}
+	if math.IsInf(f, 0) {
+		return 0, ErrOverflow
+	}
+	return f, nil
+}
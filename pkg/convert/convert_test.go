@@ -0,0 +1,368 @@
+package convert
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	type celsius float64
+	tests := []struct {
+		name string
+		conv func() (any, error)
+		want any
+		err  error
+	}{
+		{"int(25.5)", func() (any, error) { return Convert[int](25.5) }, 0, ErrTruncated},
+		{"int(50.0)", func() (any, error) { return Convert[int](50.0) }, 50, nil},
+		{"uint8(300)", func() (any, error) { return Convert[uint8](300) }, uint8(0), ErrOverflow},
+		{"uint8(255)", func() (any, error) { return Convert[uint8](255) }, uint8(255), nil},
+		{"uint(-1)", func() (any, error) { return Convert[uint](-1) }, uint(0), ErrSignLoss},
+		{"uint(-0.5)", func() (any, error) { return Convert[uint](-0.5) }, uint(0), ErrSignLoss},
+		{"int8(-128.0)", func() (any, error) { return Convert[int8](-128.0) }, int8(-128), nil},
+		{"int64(2^63)", func() (any, error) { return Convert[int64](float64(1 << 63)) }, int64(0), ErrOverflow},
+		{"float32(0.1)", func() (any, error) { return Convert[float32](0.1) }, float32(0), ErrInexact},
+		{"float32(0.5)", func() (any, error) { return Convert[float32](0.5) }, float32(0.5), nil},
+		{"float64(2^53+1)", func() (any, error) { return Convert[float64](1<<53 + 1) }, 0.0, ErrInexact},
+		{"float32(1e39)", func() (any, error) { return Convert[float32](1e39) }, float32(0), ErrOverflow},
+		{"int(NaN)", func() (any, error) { return Convert[int](math.NaN()) }, 0, ErrNaN},
+		{"int(+Inf)", func() (any, error) { return Convert[int](math.Inf(1)) }, 0, ErrInf},
+		{"float32(-Inf)", func() (any, error) { return Convert[float32](math.Inf(-1)) }, float32(math.Inf(-1)), nil},
+		{"celsius(21)", func() (any, error) { return Convert[celsius](int8(21)) }, celsius(21), nil},
+		{"Round int(2.5)", func() (any, error) { return Round[int](2.5, HalfEven) }, 2, nil},
+		{"Round int(3.5)", func() (any, error) { return Round[int](3.5, HalfEven) }, 4, nil},
+		{"Round int(-2.5) Floor", func() (any, error) { return Round[int](-2.5, Floor) }, -3, nil},
+		{"Round int(-2.5) Ceil", func() (any, error) { return Round[int](-2.5, Ceil) }, -2, nil},
+		{"Round int(-2.5) Truncate", func() (any, error) { return Round[int](-2.5, Truncate) }, -2, nil},
+		{"Round uint(-0.5) Ceil", func() (any, error) { return Round[uint](-0.5, Ceil) }, uint(0), nil},
+		{"Round uint(-0.5) Floor", func() (any, error) { return Round[uint](-0.5, Floor) }, uint(0), ErrSignLoss},
+		{"Round int8(127.5) Floor", func() (any, error) { return Round[int8](127.5, Floor) }, int8(127), nil},
+		{"Round int8(127.5) Ceil", func() (any, error) { return Round[int8](127.5, Ceil) }, int8(0), ErrOverflow},
+		{"Round float32(0.1) Floor", func() (any, error) { return Round[float32](0.1, Floor) }, float32(0.099999994), nil},
+		{"Round float32(0.1) Ceil", func() (any, error) { return Round[float32](0.1, Ceil) }, float32(0.1), nil},
+	}
+	for _, tt := range tests {
+		got, err := tt.conv()
+		if !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.err)
+		}
+		if got != tt.want {
+			t.Errorf("%s = %#v, want %#v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	type cents int64
+	_, err := Convert[cents](25.5)
+	want := "convert: float64 25.5 to convert.cents: fractional part would be lost"
+	if err == nil || err.Error() != want {
+		t.Errorf("err = %v, want %s", err, want)
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Value != "25.5" {
+		t.Errorf("errors.As(err) = %#v", e)
+	}
+}
+
+// TestPairs checks the properties of Convert and Round for every pair of
+// numeric types, on edge values and random values of the source type,
+// against math/big and Go's own conversions.
+func TestPairs(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	fromAll[int](t, r)
+	fromAll[int8](t, r)
+	fromAll[int16](t, r)
+	fromAll[int32](t, r)
+	fromAll[int64](t, r)
+	fromAll[uint](t, r)
+	fromAll[uint8](t, r)
+	fromAll[uint16](t, r)
+	fromAll[uint32](t, r)
+	fromAll[uint64](t, r)
+	fromAll[uintptr](t, r)
+	fromAll[float32](t, r)
+	fromAll[float64](t, r)
+}
+
+func fromAll[From Number](t *testing.T, r *rand.Rand) {
+	values := samples[From](r)
+	pair[int, From](t, values)
+	pair[int8, From](t, values)
+	pair[int16, From](t, values)
+	pair[int32, From](t, values)
+	pair[int64, From](t, values)
+	pair[uint, From](t, values)
+	pair[uint8, From](t, values)
+	pair[uint16, From](t, values)
+	pair[uint32, From](t, values)
+	pair[uint64, From](t, values)
+	pair[uintptr, From](t, values)
+	pair[float32, From](t, values)
+	pair[float64, From](t, values)
+}
+
+// samples returns edge values of From, values around the bounds of every
+// type, halves that test the ties of HalfEven, and random bit patterns.
+func samples[From Number](r *rand.Rand) []From {
+	k, bits := info[From]()
+	var vs []From
+	for _, i := range []int64{0, 1, -1, 2, -2, 3, 100, -100, math.MinInt64, math.MaxInt64} {
+		vs = append(vs, From(i))
+	}
+	u := uint64(math.MaxUint64)
+	vs = append(vs, From(u))
+	for _, b := range []uint{7, 8, 15, 16, 24, 31, 32, 53, 54, 63, 64} {
+		p := new(big.Float).SetMantExp(big.NewFloat(1), int(b))
+		for _, d := range []float64{-1, -0.5, 0, 0.5, 1} {
+			for _, sign := range []float64{1, -1} {
+				x := new(big.Float).Add(p, big.NewFloat(d))
+				x.Mul(x, big.NewFloat(sign))
+				if k == floatKind {
+					f, _ := x.Float64()
+					vs = append(vs, From(f))
+				} else if i, acc := x.Int64(); acc == big.Exact {
+					vs = append(vs, From(i))
+				} else if u, acc := x.Uint64(); acc == big.Exact {
+					vs = append(vs, From(u))
+				}
+			}
+		}
+	}
+	if k == floatKind {
+		for _, f := range []float64{0.1, -0.1, 0.5, -0.5, 1.5, 2.5, -2.5, 25.5, math.Copysign(0, -1),
+			math.NaN(), math.Inf(1), math.Inf(-1), math.MaxFloat32, math.SmallestNonzeroFloat32,
+			math.MaxFloat64, -math.MaxFloat64, math.SmallestNonzeroFloat64, 1e39,
+			// Just past the largest float32, and its halfway point to 2^128.
+			math.MaxFloat32 * (1 + 0x1p-30), -math.MaxFloat32 * (1 + 0x1p-30), math.MaxFloat32 + 0x1p103} {
+			vs = append(vs, From(f))
+		}
+	}
+	for range 300 {
+		b := r.Uint64()
+		switch {
+		case k != floatKind:
+			vs = append(vs, From(b))
+		case bits == 32:
+			vs = append(vs, From(math.Float32frombits(uint32(b))))
+		default:
+			vs = append(vs, From(math.Float64frombits(b)))
+		}
+		if k == floatKind {
+			// A small number of quarters, often a tie.
+			vs = append(vs, From(float64(r.IntN(2001)-1000)/4))
+		}
+	}
+	return vs
+}
+
+func pair[To, From Number](t *testing.T, values []From) {
+	t.Helper()
+	toKind, _ := info[To]()
+	name := fmt.Sprintf("%T to %T", *new(From), *new(To))
+	for _, v := range values {
+		x, inf := rat(v)
+		got, err := Convert[To](v)
+
+		switch {
+		case x == nil && inf == 0: // NaN
+			if toKind == floatKind {
+				if err != nil || !math.IsNaN(float64(got)) {
+					t.Fatalf("%s: Convert(NaN) = %v, %v, want NaN", name, got, err)
+				}
+			} else if !errors.Is(err, ErrNaN) {
+				t.Fatalf("%s: Convert(NaN): err = %v, want ErrNaN", name, err)
+			}
+			continue
+		case x == nil:
+			if toKind == floatKind {
+				if err != nil || !math.IsInf(float64(got), inf) {
+					t.Fatalf("%s: Convert(%v) = %v, %v", name, v, got, err)
+				}
+			} else if !errors.Is(err, ErrInf) {
+				t.Fatalf("%s: Convert(%v): err = %v, want ErrInf", name, v, err)
+			}
+			continue
+		}
+
+		// Convert succeeds exactly when To holds the value, and then
+		// agrees with Go's conversion.
+		ok := representable[To](x)
+		if (err == nil) != ok {
+			t.Fatalf("%s: Convert(%v) = %v, %v; representable: %v", name, v, got, err, ok)
+		}
+		if err == nil {
+			if y, _ := rat(got); y.Cmp(x) != 0 || got != To(v) {
+				t.Fatalf("%s: Convert(%v) = %v, want %v", name, v, got, To(v))
+			}
+		} else if !errors.As(err, new(*Error)) {
+			t.Fatalf("%s: Convert(%v): err %v is not an *Error", name, v, err)
+		}
+
+		for _, mode := range []Mode{Truncate, Floor, Ceil, HalfEven} {
+			checkRound[To](t, name, v, x, mode)
+		}
+	}
+}
+
+// checkRound checks that Round returns a value of To on the side of x the
+// mode asks for with no value of To between them, or an error when every
+// such value is out of range.
+func checkRound[To, From Number](t *testing.T, name string, v From, x *big.Rat, mode Mode) {
+	t.Helper()
+	got, err := Round[To](v, mode)
+	toKind, _ := info[To]()
+	lo, hi := bounds[To]()
+	if err != nil {
+		if !errors.Is(err, ErrOverflow) && !errors.Is(err, ErrSignLoss) {
+			t.Fatalf("%s: Round(%v, %v): err = %v, want a range error", name, v, mode, err)
+		}
+		// The rounded value would be out of range: for integers the
+		// nearest integer on the mode's side, for floats an infinity.
+		if toKind == floatKind {
+			var over bool
+			switch mode {
+			case Floor:
+				over = x.Cmp(lo) < 0
+			case Ceil:
+				over = x.Cmp(hi) > 0
+			case HalfEven:
+				over = math.IsInf(float64(To(v)), 0)
+			}
+			if !over {
+				t.Fatalf("%s: Round(%v, %v): %v, but the mode rounds it into range", name, v, mode, err)
+			}
+			return
+		}
+		n := roundRat(x, mode)
+		if n.Cmp(lo) >= 0 && n.Cmp(hi) <= 0 {
+			t.Fatalf("%s: Round(%v, %v): %v, but %v is in range", name, v, mode, err, n)
+		}
+		return
+	}
+
+	y, _ := rat(got)
+	cmp := y.Cmp(x)
+	switch {
+	case mode == Floor && cmp > 0, mode == Ceil && cmp < 0,
+		mode == Truncate && new(big.Rat).Abs(y).Cmp(new(big.Rat).Abs(x)) > 0:
+		t.Fatalf("%s: Round(%v, %v) = %v is on the wrong side", name, v, mode, got)
+	}
+	if toKind == floatKind {
+		// Go's float conversions round half to even.
+		if mode == HalfEven && got != To(v) {
+			t.Fatalf("%s: Round(%v, HalfEven) = %v, want %v", name, v, got, To(v))
+		}
+		// The next float toward x must lie past it.
+		if cmp != 0 {
+			other := nextFloat(got, cmp < 0)
+			if o, _ := rat(other); o != nil && o.Cmp(x) != -cmp {
+				t.Fatalf("%s: Round(%v, %v) = %v, but %v is between it and the value", name, v, mode, got, other)
+			}
+		}
+		return
+	}
+	if n := roundRat(x, mode); y.Cmp(n) != 0 {
+		t.Fatalf("%s: Round(%v, %v) = %v, want %v", name, v, mode, got, n)
+	}
+}
+
+// rat returns v as a big.Rat, or nil and the sign of an infinity, or nil
+// and 0 for NaN.
+func rat[T Number](v T) (*big.Rat, int) {
+	switch k, _ := info[T](); k {
+	case signedKind:
+		return new(big.Rat).SetInt64(int64(v)), 0
+	case unsignedKind:
+		return new(big.Rat).SetUint64(uint64(v)), 0
+	}
+	f := float64(v)
+	switch {
+	case math.IsNaN(f):
+		return nil, 0
+	case math.IsInf(f, 0):
+		if f > 0 {
+			return nil, 1
+		}
+		return nil, -1
+	}
+	return new(big.Rat).SetFloat64(f), 0
+}
+
+// bounds returns the least and greatest finite values of T.
+func bounds[T Number]() (lo, hi *big.Rat) {
+	switch k, bits := info[T](); k {
+	case signedKind:
+		lo = new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(-1), uint(bits-1)))
+		hi = new(big.Rat).Sub(new(big.Rat).Neg(lo), big.NewRat(1, 1))
+	case unsignedKind:
+		lo = new(big.Rat)
+		hi = new(big.Rat).SetInt(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(bits)), big.NewInt(1)))
+	default:
+		hi = new(big.Rat).SetFloat64(math.MaxFloat64)
+		if bits == 32 {
+			hi = new(big.Rat).SetFloat64(math.MaxFloat32)
+		}
+		lo = new(big.Rat).Neg(hi)
+	}
+	return lo, hi
+}
+
+// representable reports whether T holds x exactly.
+func representable[T Number](x *big.Rat) bool {
+	k, bits := info[T]()
+	if k == floatKind {
+		// Rat.Float32's exact result is not trusted for values that
+		// underflow; the round trip decides.
+		f, _ := x.Float64()
+		if bits == 32 {
+			f32, _ := x.Float32()
+			f = float64(f32)
+		}
+		return !math.IsInf(f, 0) && new(big.Rat).SetFloat64(f).Cmp(x) == 0
+	}
+	lo, hi := bounds[T]()
+	return x.IsInt() && x.Cmp(lo) >= 0 && x.Cmp(hi) <= 0
+}
+
+// roundRat rounds x to an integer, the slow way.
+func roundRat(x *big.Rat, mode Mode) *big.Rat {
+	q, m := new(big.Int).DivMod(x.Num(), x.Denom(), new(big.Int)) // q = floor(x)
+	floor := new(big.Rat).SetInt(q)
+	if m.Sign() == 0 {
+		return floor
+	}
+	ceil := new(big.Rat).Add(floor, big.NewRat(1, 1))
+	switch mode {
+	case Floor:
+		return floor
+	case Ceil:
+		return ceil
+	case Truncate:
+		if x.Sign() < 0 {
+			return ceil
+		}
+		return floor
+	}
+	switch d := new(big.Rat).Sub(x, floor).Cmp(big.NewRat(1, 2)); {
+	case d < 0, d == 0 && q.Bit(0) == 0:
+		return floor
+	}
+	return ceil
+}
+
+// nextFloat returns the float of T after f, upward or downward.
+func nextFloat[T Number](f T, up bool) T {
+	dir := math.Inf(-1)
+	if up {
+		dir = math.Inf(1)
+	}
+	if _, bits := info[T](); bits == 32 {
+		return T(math.Nextafter32(float32(f), float32(dir)))
+	}
+	return T(math.Nextafter(float64(f), dir))
+}
@@ -0,0 +1,529 @@
+// Package expr evaluates Go arithmetic, comparison and logical expressions
+// one operation at a time, recording each step in the order Go performs
+// it. It backs the operator precedence part of Day 7, where
+// 2 + 3*4 is explained as "3 * 4 = 12, then 2 + 12 = 14".
+//
+// Expressions are parsed with go/parser and type-checked with go/types,
+// so they mean exactly what they mean in a Go program: constant
+// expressions are exact and an out-of-range constant is a compile error,
+// while operations on variables use the variables' types, so integer
+// division truncates, int8(127) + 1 wraps to -128 and dividing an integer
+// variable by zero fails as a run-time panic would. && and || do not
+// evaluate their right operand when the left one decides the result.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Vars gives values to the identifiers of an expression. A value may be
+// of any integer, float, bool or string type, and the variable has that
+// type, as if declared with :=.
+type Vars map[string]any
+
+// Result is an evaluated expression.
+type Result struct {
+	Expr  string `json:"expr"`  // the expression as given
+	Paren string `json:"paren"` // the expression with every operation in parentheses
+	Value string `json:"value"`
+	Type  string `json:"type"`  // e.g. "int8", or "untyped int" for a constant
+	Const bool   `json:"const"` // a constant expression, evaluated by the compiler
+	Steps []Step `json:"steps"`
+}
+
+// Step is one operation.
+type Step struct {
+	Expr  string `json:"expr"` // the operation as written, e.g. "b*c"
+	Op    string `json:"op"`   // the operator, or the type of a conversion
+	X     string `json:"x"`    // an operand as written, or the value of the step that computed it
+	Y     string `json:"y,omitempty"`
+	Value string `json:"value"`
+	Type  string `json:"type"`
+
+	Unary      bool `json:"unary,omitempty"`
+	Conversion bool `json:"conversion,omitempty"`
+	Skipped    bool `json:"skipped,omitempty"` // && or || decided by X; Y was not evaluated
+	Wrapped    bool `json:"wrapped,omitempty"` // the exact result did not fit Type and wrapped around
+}
+
+// String prints the step as in the lesson, e.g. "3 * 4 = 12".
+func (s Step) String() string {
+	var op string
+	switch {
+	case s.Conversion:
+		op = s.Op + "(" + s.X + ")"
+	case s.Unary:
+		op = s.Op + s.X
+	case s.Skipped:
+		op = s.X + " " + s.Op + " ..."
+	default:
+		op = s.X + " " + s.Op + " " + s.Y
+	}
+	out := op + " = " + s.Value
+	if s.Skipped {
+		out += " (the right side is not evaluated)"
+	}
+	if s.Wrapped {
+		out += " (overflows " + s.Type + " and wraps around)"
+	}
+	return out
+}
+
+// Explain joins the steps into one sentence, e.g.
+// "3 * 4 = 12, then 2 + 12 = 14".
+func (r *Result) Explain() string {
+	var parts []string
+	for _, s := range r.Steps {
+		parts = append(parts, s.String())
+	}
+	if len(parts) == 0 {
+		return r.Expr + " = " + r.Value
+	}
+	return strings.Join(parts, ", then ")
+}
+
+// WriteText writes the expression, its parenthesised form, one numbered
+// line per step and the result.
+func (r *Result) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", r.Expr)
+	if r.Paren != r.Expr {
+		fmt.Fprintf(&b, "  reads as %s\n", r.Paren)
+	}
+	for i, s := range r.Steps {
+		fmt.Fprintf(&b, "  %d. %s\n", i+1, s)
+	}
+	kind := r.Type
+	if r.Const {
+		kind += " constant"
+	}
+	fmt.Fprintf(&b, "  = %s (%s)\n", r.Value, kind)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ErrRuntime is matched, via errors.Is, by the errors of operations that
+// would panic at run time, such as an integer division by zero.
+var ErrRuntime = errors.New("runtime error")
+
+// Eval evaluates src with the given variables, which may be nil. Syntax
+// and type errors, including constant overflow, are returned as the
+// compiler would report them.
+func Eval(src string, vars Vars) (*Result, error) {
+	fset := token.NewFileSet()
+	e, err := parser.ParseExprFrom(fset, "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+	pkg := types.NewPackage("expr", "expr")
+	for name, v := range vars {
+		t, ok := basicType(reflect.TypeOf(v))
+		if !ok {
+			return nil, fmt.Errorf("variable %s: unsupported type %T", name, v)
+		}
+		pkg.Scope().Insert(types.NewVar(token.NoPos, pkg, name, t))
+	}
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}, Uses: map[*ast.Ident]types.Object{}}
+	if err := types.CheckExpr(fset, pkg, token.NoPos, e, info); err != nil {
+		return nil, err
+	}
+
+	ev := &evaluator{
+		src:   src,
+		fset:  fset,
+		info:  info,
+		vars:  vars,
+		sizes: types.SizesFor("gc", runtime.GOARCH),
+	}
+	v, _, err := ev.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	tv := info.Types[e]
+	return &Result{
+		Expr:  src,
+		Paren: ev.paren(e, true),
+		Value: v.String(),
+		Type:  v.t.String(),
+		Const: tv.Value != nil,
+		Steps: ev.steps,
+	}, nil
+}
+
+// basicType returns the go/types type of a Go value's kind.
+func basicType(t reflect.Type) (types.Type, bool) {
+	if t == nil {
+		return nil, false
+	}
+	kinds := map[reflect.Kind]types.BasicKind{
+		reflect.Bool: types.Bool, reflect.String: types.String,
+		reflect.Int: types.Int, reflect.Int8: types.Int8, reflect.Int16: types.Int16,
+		reflect.Int32: types.Int32, reflect.Int64: types.Int64,
+		reflect.Uint: types.Uint, reflect.Uint8: types.Uint8, reflect.Uint16: types.Uint16,
+		reflect.Uint32: types.Uint32, reflect.Uint64: types.Uint64, reflect.Uintptr: types.Uintptr,
+		reflect.Float32: types.Float32, reflect.Float64: types.Float64,
+	}
+	k, ok := kinds[t.Kind()]
+	if !ok {
+		return nil, false
+	}
+	return types.Typ[k], true
+}
+
+// value is an operand: a constant.Value for integers, booleans, strings
+// and constant floats, or a float64 for float variables, which can hold
+// infinities and NaN.
+type value struct {
+	t types.Type
+	c constant.Value
+	f float64
+}
+
+func (v value) isFloat() bool { return v.c == nil }
+
+func (v value) String() string {
+	bits := 64
+	if basic, ok := v.t.Underlying().(*types.Basic); ok && basic.Kind() == types.Float32 {
+		bits = 32
+	}
+	if v.isFloat() || v.c.Kind() == constant.Float {
+		return strconv.FormatFloat(v.float(), 'g', -1, bits)
+	}
+	switch v.c.Kind() {
+	case constant.String:
+		return strconv.Quote(constant.StringVal(v.c))
+	}
+	return v.c.ExactString()
+}
+
+func (v value) float() float64 {
+	if v.isFloat() {
+		return v.f
+	}
+	f, _ := constant.Float64Val(v.c)
+	return f
+}
+
+type evaluator struct {
+	src   string
+	fset  *token.FileSet
+	info  *types.Info
+	vars  Vars
+	sizes types.Sizes
+	steps []Step
+}
+
+// text returns the source of n as written.
+func (ev *evaluator) text(n ast.Node) string {
+	return ev.src[ev.fset.Position(n.Pos()).Offset:ev.fset.Position(n.End()).Offset]
+}
+
+// eval evaluates e and returns its value with the way later steps show
+// it: leaves as written, operations by their value.
+func (ev *evaluator) eval(e ast.Expr) (value, string, error) {
+	tv := ev.info.Types[e]
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		return ev.eval(e.X)
+	case *ast.BasicLit:
+		return value{t: tv.Type, c: tv.Value}, e.Value, nil
+	case *ast.Ident:
+		if tv.Value != nil {
+			return value{t: tv.Type, c: tv.Value}, e.Name, nil // true, false or iota
+		}
+		return ev.variable(e, tv.Type), e.Name, nil
+	case *ast.UnaryExpr:
+		if e.Op != token.ADD && e.Op != token.SUB && e.Op != token.NOT && e.Op != token.XOR {
+			break
+		}
+		x, xs, err := ev.eval(e.X)
+		if err != nil {
+			return value{}, "", err
+		}
+		v, wrapped := ev.unary(e.Op, x, tv)
+		return ev.step(Step{Expr: ev.text(e), Op: e.Op.String(), X: xs, Unary: true, Wrapped: wrapped}, v)
+	case *ast.BinaryExpr:
+		return ev.binary(e, tv)
+	case *ast.CallExpr:
+		if !tv.IsValue() || len(e.Args) != 1 || !ev.info.Types[e.Fun].IsType() {
+			break
+		}
+		x, xs, err := ev.eval(e.Args[0])
+		if err != nil {
+			return value{}, "", err
+		}
+		if tv.Value == nil && !(isNumeric(tv.Type) && isNumeric(x.t)) && !types.Identical(tv.Type.Underlying(), x.t.Underlying()) {
+			break
+		}
+		v, wrapped := ev.convert(x, tv)
+		return ev.step(Step{Expr: ev.text(e), Op: ev.text(e.Fun), X: xs, Conversion: true, Wrapped: wrapped}, v)
+	}
+	return value{}, "", fmt.Errorf("%s: only operators, literals, variables and conversions are supported", ev.text(e))
+}
+
+// step records s with the value v and returns v.
+func (ev *evaluator) step(s Step, v value) (value, string, error) {
+	s.Value, s.Type = v.String(), v.t.String()
+	ev.steps = append(ev.steps, s)
+	return v, s.Value, nil
+}
+
+func (ev *evaluator) variable(id *ast.Ident, t types.Type) value {
+	rv := reflect.ValueOf(ev.vars[id.Name])
+	switch {
+	case rv.CanInt():
+		return value{t: t, c: constant.MakeInt64(rv.Int())}
+	case rv.CanUint():
+		return value{t: t, c: constant.MakeUint64(rv.Uint())}
+	case rv.CanFloat():
+		return value{t: t, f: rv.Float()}
+	case rv.Kind() == reflect.Bool:
+		return value{t: t, c: constant.MakeBool(rv.Bool())}
+	}
+	return value{t: t, c: constant.MakeString(rv.String())}
+}
+
+func (ev *evaluator) binary(e *ast.BinaryExpr, tv types.TypeAndValue) (value, string, error) {
+	x, xs, err := ev.eval(e.X)
+	if err != nil {
+		return value{}, "", err
+	}
+	s := Step{Expr: ev.text(e), Op: e.Op.String(), X: xs}
+	if e.Op == token.LAND || e.Op == token.LOR {
+		if constant.BoolVal(x.c) == (e.Op == token.LOR) {
+			s.Skipped = true
+			return ev.step(s, value{t: tv.Type, c: x.c})
+		}
+	}
+	y, ys, err := ev.eval(e.Y)
+	if err != nil {
+		return value{}, "", err
+	}
+	s.Y = ys
+	if tv.Value != nil {
+		return ev.step(s, value{t: tv.Type, c: tv.Value})
+	}
+	if (e.Op == token.SHL || e.Op == token.SHR) && !isInteger(types.Default(tv.Type)) {
+		// 1.0 << n is not a constant, so 1.0 takes its default type.
+		return value{}, "", fmt.Errorf("invalid operation: shifted operand %s (type %s) must be integer", xs, types.Default(tv.Type))
+	}
+	v, wrapped, err := ev.operate(e.Op, x, y, tv.Type)
+	if err != nil {
+		return value{}, "", fmt.Errorf("%w: %v in %s", ErrRuntime, err, s.Expr)
+	}
+	s.Wrapped = wrapped
+	return ev.step(s, v)
+}
+
+// operate applies a binary operator to operands of the same type, as
+// compiled code does, giving a result of type t. An untyped t, as in the
+// non-constant shift 1 << n, stands for its default type.
+func (ev *evaluator) operate(op token.Token, x, y value, t types.Type) (value, bool, error) {
+	t = types.Default(t)
+	switch op {
+	case token.LAND, token.LOR:
+		return value{t: t, c: y.c}, false, nil // x did not decide
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		var res bool
+		if x.isFloat() || y.isFloat() || isFloat(x.t) {
+			res = compareFloats(op, x.float(), y.float())
+		} else {
+			res = constant.Compare(x.c, op, y.c)
+		}
+		return value{t: t, c: constant.MakeBool(res)}, false, nil
+	}
+
+	if isFloat(t) {
+		a, b := x.float(), y.float()
+		var f float64
+		switch op {
+		case token.ADD:
+			f = a + b
+		case token.SUB:
+			f = a - b
+		case token.MUL:
+			f = a * b
+		case token.QUO:
+			f = a / b
+		}
+		return value{t: t, f: ev.round(f, t)}, false, nil
+	}
+	if !isInteger(t) {
+		return value{t: t, c: constant.BinaryOp(x.c, op, y.c)}, false, nil // string concatenation
+	}
+
+	var exact constant.Value
+	switch op {
+	case token.QUO, token.REM:
+		if constant.Sign(y.c) == 0 {
+			return value{}, false, errors.New("integer divide by zero")
+		}
+		if op == token.QUO {
+			op = token.QUO_ASSIGN // truncated integer division
+		}
+		exact = constant.BinaryOp(x.c, op, y.c)
+	case token.SHL, token.SHR:
+		if constant.Sign(y.c) < 0 {
+			return value{}, false, errors.New("negative shift amount")
+		}
+		n, ok := constant.Uint64Val(y.c)
+		if !ok || n > 128 {
+			n = 128 // past every type's width: the result no longer changes
+		}
+		exact = constant.Shift(x.c, op, uint(n))
+	default:
+		exact = constant.BinaryOp(x.c, op, y.c)
+	}
+	v := ev.wrap(exact, t)
+	return value{t: t, c: v}, constant.Compare(v, token.NEQ, exact), nil
+}
+
+func compareFloats(op token.Token, a, b float64) bool {
+	switch op {
+	case token.EQL:
+		return a == b
+	case token.NEQ:
+		return a != b
+	case token.LSS:
+		return a < b
+	case token.LEQ:
+		return a <= b
+	case token.GTR:
+		return a > b
+	}
+	return a >= b
+}
+
+func (ev *evaluator) unary(op token.Token, x value, tv types.TypeAndValue) (value, bool) {
+	if tv.Value != nil {
+		return value{t: tv.Type, c: tv.Value}, false
+	}
+	t := types.Default(tv.Type)
+	switch {
+	case op == token.NOT:
+		return value{t: t, c: constant.MakeBool(!constant.BoolVal(x.c))}, false
+	case isFloat(t):
+		if op == token.SUB {
+			return value{t: t, f: -x.float()}, false
+		}
+		return value{t: t, f: x.float()}, false
+	case op == token.XOR:
+		// Bitwise complement: on the type's width when unsigned, and as
+		// -x-1 when signed.
+		prec := uint(0)
+		if isUnsigned(t) {
+			prec = uint(8 * ev.sizes.Sizeof(t))
+		}
+		return value{t: t, c: constant.UnaryOp(op, x.c, prec)}, false
+	}
+	exact := constant.UnaryOp(op, x.c, 0)
+	v := ev.wrap(exact, t)
+	return value{t: t, c: v}, constant.Compare(v, token.NEQ, exact)
+}
+
+// convert converts x to the type of the conversion expression.
+func (ev *evaluator) convert(x value, tv types.TypeAndValue) (value, bool) {
+	t := tv.Type
+	switch {
+	case tv.Value != nil:
+		return value{t: t, c: tv.Value}, false
+	case isFloat(t):
+		return value{t: t, f: ev.round(x.float(), t)}, false
+	case isInteger(t):
+		var exact constant.Value
+		if x.isFloat() {
+			// Truncate toward zero. Out-of-range results are implementation
+			// specific in Go; they wrap here.
+			f := math.Trunc(x.f)
+			if math.IsInf(f, 0) || math.IsNaN(f) {
+				f = 0
+			}
+			exact = constant.Make(new(big.Float).SetFloat64(f))
+			exact = constant.ToInt(exact)
+		} else {
+			exact = x.c
+		}
+		v := ev.wrap(exact, t)
+		return value{t: t, c: v}, constant.Compare(v, token.NEQ, exact)
+	}
+	return value{t: t, c: x.c}, false
+}
+
+// wrap reduces the integer v modulo 2^bits of t, into t's range. The
+// sizes of untyped types are those of their default types.
+func (ev *evaluator) wrap(v constant.Value, t types.Type) constant.Value {
+	t = types.Default(t)
+	bits := uint(8 * ev.sizes.Sizeof(t))
+	n, ok := constant.Val(v).(*big.Int)
+	if !ok {
+		n = big.NewInt(constant.Val(v).(int64))
+	}
+	mod := new(big.Int).Lsh(big.NewInt(1), bits)
+	n = new(big.Int).Mod(n, mod) // 0 <= n < 2^bits
+	if !isUnsigned(t) && n.Bit(int(bits)-1) == 1 {
+		n.Sub(n, mod)
+	}
+	return constant.Make(n)
+}
+
+// round rounds f to the precision of the float type t.
+func (ev *evaluator) round(f float64, t types.Type) float64 {
+	if t.Underlying().(*types.Basic).Kind() == types.Float32 {
+		return float64(float32(f))
+	}
+	return f
+}
+
+func isFloat(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsFloat != 0 && b.Info()&types.IsUntyped == 0
+}
+
+func isNumeric(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&(types.IsInteger|types.IsFloat) != 0
+}
+
+func isInteger(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsInteger != 0
+}
+
+func isUnsigned(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsUnsigned != 0
+}
+
+// paren writes e with every operation below the top in parentheses, so
+// that the order no longer depends on precedence: 2 + 3*4 becomes
+// 2 + (3 * 4).
+func (ev *evaluator) paren(e ast.Expr, top bool) string {
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		return ev.paren(e.X, top)
+	case *ast.BinaryExpr:
+		s := ev.paren(e.X, false) + " " + e.Op.String() + " " + ev.paren(e.Y, false)
+		if top {
+			return s
+		}
+		return "(" + s + ")"
+	case *ast.UnaryExpr:
+		return e.Op.String() + ev.paren(e.X, false)
+	case *ast.CallExpr:
+		return ev.text(e.Fun) + "(" + ev.paren(e.Args[0], true) + ")"
+	}
+	return ev.text(e)
+}
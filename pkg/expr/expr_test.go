@@ -0,0 +1,158 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Each case is also written as Go, so the compiler provides the expected
+// value.
+var (
+	a, b, c = 5, 3, 2
+	x, y, z = 10, 5, 3
+	i8      = int8(100)
+	u8      = uint8(3)
+	f32     = float32(0.1)
+	f64     = 0.1
+	zero    = 0
+	s       = "go"
+	n, w    = uint(3), uint(70)
+)
+
+var vars = Vars{"a": a, "b": b, "c": c, "x": x, "y": y, "z": z, "i8": i8, "u8": u8, "f32": f32, "f64": f64, "zero": zero, "s": s, "n": n, "w": w}
+
+func TestValues(t *testing.T) {
+	tests := []struct {
+		src  string
+		want any
+	}{
+		{"2 + 3*4", 2 + 3*4},
+		{"(2 + 3) * 4", (2 + 3) * 4},
+		{"(5 + 10) / 2", (5 + 10) / 2},
+		{"10 + 15%4", 10 + 15%4},
+		{"-7 / 2", -7 / 2},
+		{"-7 % 3", -7 % 3},
+		{"7 / 2.0", 7 / 2.0},
+		{"1 << 100 >> 98", 1 << 100 >> 98},
+		{"a+b*c > 10", a+b*c > 10},
+		{"x > 5 && y < 3 || z == 3", x > 5 && y < 3 || z == 3},
+		{"x > 5 && (y < 3 || z == 3)", x > 5 && (y < 3 || z == 3)},
+		{"-a / b", -a / b},
+		{"-a % b", -a % b},
+		{"i8 + i8", i8 + i8},
+		{"i8 * 3", i8 * 3},
+		{"-(i8 + 28)", -(i8 + 28)},
+		{"u8 - 4", u8 - 4},
+		{"^u8", ^u8},
+		{"-u8", -u8},
+		{"^a", ^a},
+		{"u8 << 7", u8 << 7},
+		{"u8 << (b * 3)", u8 << (b * 3)},
+		{"-i8 >> (b * 3)", -i8 >> (b * 3)},
+		{"int8(a * 100)", int8(a * 100)},
+		{"float32(a) / 3", float32(a) / 3},
+		{"f32 + 0.2", f32 + 0.2},
+		{"f64 + 0.2", f64 + 0.2},
+		{"f64 / float64(zero)", f64 / float64(zero)},
+		{"int(f64 * 25)", int(f64 * 25)},
+		{"s + \"pher\" == \"gopher\"", s+"pher" == "gopher"},
+		{"s < \"python\"", s < "python"},
+		{"!(a > b) || a&b == 1", !(a > b) || a&b == 1},
+		{"a &^ 4 | 8 ^ 1", a&^4 | 8 ^ 1},
+		// Non-constant shifts of untyped constants take the default type,
+		// or the type the context gives them.
+		{"1 << n", 1 << n},
+		{"1<<n + 1", 1<<n + 1},
+		{"-(1 << n)", -(1 << n)},
+		{"^(1 << n)", ^(1 << n)},
+		{"1 << w", 1 << w},
+		{"int8(1 << (n + 4))", int8(1 << (n + 4))},
+		{"'a' << n", 'a' << n},
+	}
+	for _, tt := range tests {
+		r, err := Eval(tt.src, vars)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", tt.src, err)
+			continue
+		}
+		want := fmt.Sprint(tt.want)
+		if s, ok := tt.want.(string); ok {
+			want = fmt.Sprintf("%q", s)
+		}
+		if r.Value != want {
+			t.Errorf("Eval(%q) = %s, want %s", tt.src, r.Value, want)
+		}
+	}
+}
+
+func TestSteps(t *testing.T) {
+	tests := []struct {
+		src, explain, paren string
+	}{
+		{"2 + 3*4", "3 * 4 = 12, then 2 + 12 = 14", "2 + (3 * 4)"},
+		{"(5 + 10) / 2", "5 + 10 = 15, then 15 / 2 = 7", "(5 + 10) / 2"},
+		{"a+b*c > 10", "b * c = 6, then a + 6 = 11, then 11 > 10 = true", "(a + (b * c)) > 10"},
+		{"x < 5 && y/zero > 1", "x < 5 = false, then false && ... = false (the right side is not evaluated)", "(x < 5) && ((y / zero) > 1)"},
+		{"i8 + 28", "i8 + 28 = -128 (overflows int8 and wraps around)", "i8 + 28"},
+		{"-(2 + 3)", "2 + 3 = 5, then -5 = -5", "-(2 + 3)"},
+		{"int8(a) * 30", "int8(a) = 5, then 5 * 30 = -106 (overflows int8 and wraps around)", "int8(a) * 30"},
+		{"1<<n + 1", "1 << n = 8, then 8 + 1 = 9", "(1 << n) + 1"},
+	}
+	for _, tt := range tests {
+		r, err := Eval(tt.src, vars)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", tt.src, err)
+			continue
+		}
+		if got := r.Explain(); got != tt.explain {
+			t.Errorf("Eval(%q).Explain() =\n\t%s\nwant\n\t%s", tt.src, got, tt.explain)
+		}
+		if r.Paren != tt.paren {
+			t.Errorf("Eval(%q).Paren = %s, want %s", tt.src, r.Paren, tt.paren)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		want    string
+		runtime bool
+	}{
+		{"int8(100) + 100", "overflows int8", false},
+		{"10 / 0", "division by zero", false},
+		{"a / zero", "integer divide by zero", true},
+		{"a << -b", "negative shift amount", true},
+		{"a + \"1\"", "mismatched types", false},
+		{"len(s)", "only operators", false},
+		{"2 +", "expected operand", false},
+		{"1.0 << n", "shifted operand 1.0 (type float64) must be integer", false},
+	}
+	for _, tt := range tests {
+		_, err := Eval(tt.src, vars)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Eval(%q): err = %v, want one containing %q", tt.src, err, tt.want)
+			continue
+		}
+		if errors.Is(err, ErrRuntime) != tt.runtime {
+			t.Errorf("Eval(%q): errors.Is(err, ErrRuntime) = %v, want %v", tt.src, !tt.runtime, tt.runtime)
+		}
+	}
+}
+
+// A non-constant expression has no untyped result: 1 << n is an int, as
+// in v := 1 << n.
+func TestUntypedShiftType(t *testing.T) {
+	for src, want := range map[string]string{"1 << n": "int", "'a' << n": "rune", "1<<n == 8": "bool", "1 << 3": "untyped int"} {
+		r, err := Eval(src, vars)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", src, err)
+			continue
+		}
+		if r.Type != want {
+			t.Errorf("Eval(%q).Type = %s, want %s", src, r.Type, want)
+		}
+	}
+}
@@ -0,0 +1,387 @@
+// Package flags is a typed bitset of named flags, the permission example
+// of Codes/Day7/04_BitwiseOperators made reusable. Instead of bare ints
+//
+//	read := 1; write := 2; execute := 4
+//	canRead := (user1 & read) != 0
+//
+// code declares a named type, registers its flags once, and works with
+// Flags values:
+//
+//	type Perm uint8
+//
+//	var (
+//		Read    = flags.Register[Perm]("read", 'r')
+//		Write   = flags.Register[Perm]("write", 'w')
+//		Execute = flags.Register[Perm]("execute", 'x')
+//	)
+//
+//	user1 := Read.Set(Execute)  // r-x
+//	user1.Has(Read)             // true
+//
+// Registrations belong to the type, so a Flags value needs nothing else
+// to print itself as "r-x", to be parsed from "r-x", "read|execute" or
+// octal "5", and to round-trip through JSON. Register assigns bits from
+// the lowest up, as the lesson does; Define takes an explicit bit, for
+// layouts such as Unix modes where read is 4 and execute 1.
+//
+// Flags values are immutable: Set, Clear and Toggle return a new value.
+package flags
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/bits"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// Bits is the type flags are stored in: any unsigned integer type,
+// usually a named one such as type Perm uint8.
+type Bits interface {
+	~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uint
+}
+
+// Flags is a set of the flags registered for T.
+type Flags[T Bits] struct {
+	bits T
+}
+
+// Flag describes a registered flag.
+type Flag struct {
+	Name string
+	Char rune   // the letter String shows when the flag is set
+	Bit  uint64 // the flag's single bit
+}
+
+// registry holds the flags of one type, in registration order.
+type registry struct {
+	flags []Flag
+	used  uint64
+}
+
+var (
+	mu         sync.RWMutex
+	registries = map[reflect.Type]*registry{}
+)
+
+// Register registers a flag on the lowest bit of T not yet used and
+// returns it. It panics if T has no free bit or if the name or character
+// is already registered for T, so it belongs in package-level var
+// declarations.
+func Register[T Bits](name string, char rune) Flags[T] {
+	mu.Lock()
+	defer mu.Unlock()
+	var used uint64
+	if r := registries[reflect.TypeFor[T]()]; r != nil {
+		used = r.used
+	}
+	free := ^used & mask[T]()
+	if free == 0 {
+		panic(fmt.Sprintf("flags: %s: no free bit for flag %q", reflect.TypeFor[T](), name))
+	}
+	return define(T(free&-free), name, char)
+}
+
+// Define registers a flag on the given bit and returns it. It panics if
+// bit is not a single bit, if the bit, the name or the character is
+// already registered for T, or if the character is reserved: '-', '+'
+// and the digits.
+func Define[T Bits](bit T, name string, char rune) Flags[T] {
+	mu.Lock()
+	defer mu.Unlock()
+	return define(bit, name, char)
+}
+
+// define is Define with mu held.
+func define[T Bits](bit T, name string, char rune) Flags[T] {
+	typ := reflect.TypeFor[T]()
+	b := uint64(bit)
+	switch {
+	case bits.OnesCount64(b) != 1:
+		panic(fmt.Sprintf("flags: %s: flag %q: %#x is not a single bit", typ, name, b))
+	case name == "" || strings.ContainsAny(name, "|, "):
+		panic(fmt.Sprintf("flags: %s: invalid flag name %q", typ, name))
+	case char == '-' || char == '+' || char >= '0' && char <= '9':
+		panic(fmt.Sprintf("flags: %s: flag %q: character %q is reserved", typ, name, char))
+	}
+
+	r := registries[typ]
+	if r == nil {
+		r = &registry{}
+		registries[typ] = r
+	}
+	for _, f := range r.flags {
+		switch {
+		case f.Bit == b:
+			panic(fmt.Sprintf("flags: %s: bit %#x is already flag %q", typ, b, f.Name))
+		case f.Name == name:
+			panic(fmt.Sprintf("flags: %s: flag %q registered twice", typ, name))
+		case f.Char == char:
+			panic(fmt.Sprintf("flags: %s: character %q is already flag %q", typ, char, f.Name))
+		}
+	}
+	r.flags = append(r.flags, Flag{Name: name, Char: char, Bit: b})
+	r.used |= b
+	return Flags[T]{bit}
+}
+
+// Registered returns the flags registered for T, in registration order.
+func Registered[T Bits]() []Flag {
+	mu.RLock()
+	defer mu.RUnlock()
+	if r := registries[reflect.TypeFor[T]()]; r != nil {
+		return append([]Flag(nil), r.flags...)
+	}
+	return nil
+}
+
+// All returns every flag registered for T.
+func All[T Bits]() Flags[T] {
+	var all T
+	for _, f := range Registered[T]() {
+		all |= T(f.Bit)
+	}
+	return Flags[T]{all}
+}
+
+// Of returns the flags whose bits are set in b.
+func Of[T Bits](b T) Flags[T] {
+	return Flags[T]{b}
+}
+
+// mask returns the bits T can hold.
+func mask[T Bits]() uint64 {
+	var zero T
+	return ^uint64(0) >> (64 - 8*unsafe.Sizeof(zero))
+}
+
+// Bits returns the flags as an integer.
+func (f Flags[T]) Bits() T { return f.bits }
+
+// IsZero reports whether no flag is set.
+func (f Flags[T]) IsZero() bool { return f.bits == 0 }
+
+// Set returns f with the flags of g also set: f | g.
+func (f Flags[T]) Set(g ...Flags[T]) Flags[T] {
+	for _, x := range g {
+		f.bits |= x.bits
+	}
+	return f
+}
+
+// Clear returns f with the flags of g cleared: f &^ g.
+func (f Flags[T]) Clear(g ...Flags[T]) Flags[T] {
+	for _, x := range g {
+		f.bits &^= x.bits
+	}
+	return f
+}
+
+// Toggle returns f with the flags of g flipped: f ^ g.
+func (f Flags[T]) Toggle(g ...Flags[T]) Flags[T] {
+	for _, x := range g {
+		f.bits ^= x.bits
+	}
+	return f
+}
+
+// Has reports whether every flag of g is set in f. It is false when g has
+// no flags, unlike HasAll with no arguments.
+func (f Flags[T]) Has(g Flags[T]) bool {
+	return g.bits != 0 && f.bits&g.bits == g.bits
+}
+
+// HasAll reports whether every flag of every g is set in f.
+func (f Flags[T]) HasAll(g ...Flags[T]) bool {
+	for _, x := range g {
+		if f.bits&x.bits != x.bits {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAny reports whether any flag of any g is set in f.
+func (f Flags[T]) HasAny(g ...Flags[T]) bool {
+	for _, x := range g {
+		if f.bits&x.bits != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the names of the flags set in f, in registration order.
+func (f Flags[T]) Names() []string {
+	var names []string
+	for _, fl := range Registered[T]() {
+		if uint64(f.bits)&fl.Bit != 0 {
+			names = append(names, fl.Name)
+		}
+	}
+	return names
+}
+
+// String shows one character per registered flag, in registration order:
+// the flag's letter when it is set and "-" when it is not, as in "r-x".
+// Set bits that no flag was registered for follow in octal, as in
+// "r-x+0o200".
+func (f Flags[T]) String() string {
+	var b strings.Builder
+	known := uint64(0)
+	for _, fl := range Registered[T]() {
+		known |= fl.Bit
+		if uint64(f.bits)&fl.Bit != 0 {
+			b.WriteRune(fl.Char)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	if rest := uint64(f.bits) &^ known; rest != 0 {
+		fmt.Fprintf(&b, "+%O", rest)
+	}
+	return b.String()
+}
+
+// Octal returns the flags' bits in octal, as chmod writes them: "5".
+func (f Flags[T]) Octal() string {
+	return strconv.FormatUint(uint64(f.bits), 8)
+}
+
+// ErrSyntax is matched, via errors.Is, by every error of Parse.
+var ErrSyntax = errors.New("invalid flags")
+
+// Parse reads flags in any of the forms Flags produces or users write:
+//
+//	r-x            one character per registered flag, "-" for unset
+//	r-x+0o200      the same, followed by unregistered bits in octal
+//	read|execute   names separated by "|" or ","
+//	5, 0o5, 05     octal bits
+//
+// The empty string is no flags.
+func Parse[T Bits](s string) (Flags[T], error) {
+	if s == "" {
+		return Flags[T]{}, nil
+	}
+	if chars, rest, ok := strings.Cut(s, "+"); ok {
+		return parseExtra[T](s, chars, rest)
+	}
+	if s[0] >= '0' && s[0] <= '9' {
+		digits := strings.TrimPrefix(strings.TrimPrefix(s, "0o"), "0O")
+		n, err := strconv.ParseUint(digits, 8, 64)
+		if err != nil || n&^mask[T]() != 0 {
+			return Flags[T]{}, fmt.Errorf("%w %q: not an octal %s", ErrSyntax, s, reflect.TypeFor[T]())
+		}
+		return Flags[T]{T(n)}, nil
+	}
+	registered := Registered[T]()
+	if f, ok := parseChars[T](s, registered); ok {
+		return f, nil
+	}
+	var f Flags[T]
+	for _, name := range strings.FieldsFunc(s, func(r rune) bool { return r == '|' || r == ',' }) {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, fl := range registered {
+			if fl.Name == name {
+				f.bits |= T(fl.Bit)
+				found = true
+			}
+		}
+		if !found {
+			return Flags[T]{}, fmt.Errorf("%w %q: no %s flag %q", ErrSyntax, s, reflect.TypeFor[T](), name)
+		}
+	}
+	return f, nil
+}
+
+// parseExtra parses the String form of flags with unregistered bits set:
+// the characters of the registered flags, then "+" and the other bits in
+// octal.
+func parseExtra[T Bits](s, chars, rest string) (Flags[T], error) {
+	registered := Registered[T]()
+	f, ok := parseChars[T](chars, registered)
+	digits, octal := strings.CutPrefix(rest, "0o")
+	n, err := strconv.ParseUint(digits, 8, 64)
+	if !ok || !octal || err != nil || n == 0 {
+		return Flags[T]{}, fmt.Errorf("%w %q: want the form %q", ErrSyntax, s, Flags[T]{}.String()+"+0o...")
+	}
+	var known uint64
+	for _, fl := range registered {
+		known |= fl.Bit
+	}
+	if n&known != 0 || n&^mask[T]() != 0 {
+		return Flags[T]{}, fmt.Errorf("%w %q: bits %O are not unregistered bits of %s", ErrSyntax, s, n, reflect.TypeFor[T]())
+	}
+	f.bits |= T(n)
+	return f, nil
+}
+
+// parseChars parses the String form: one rune per registered flag.
+func parseChars[T Bits](s string, registered []Flag) (Flags[T], bool) {
+	runes := []rune(s)
+	if len(runes) != len(registered) {
+		return Flags[T]{}, false
+	}
+	var f Flags[T]
+	for i, r := range runes {
+		switch r {
+		case registered[i].Char:
+			f.bits |= T(registered[i].Bit)
+		case '-':
+		default:
+			return Flags[T]{}, false
+		}
+	}
+	return f, true
+}
+
+// MarshalJSON encodes the flags as the list of their names, such as
+// ["read","execute"]. Bits without a registered name are an error.
+func (f Flags[T]) MarshalJSON() ([]byte, error) {
+	names := f.Names()
+	if names == nil {
+		names = []string{}
+	}
+	var known uint64
+	for _, fl := range Registered[T]() {
+		known |= fl.Bit
+	}
+	if rest := uint64(f.bits) &^ known; rest != 0 {
+		return nil, fmt.Errorf("flags: %s: bits %#o have no name", reflect.TypeFor[T](), rest)
+	}
+	return json.Marshal(names)
+}
+
+// UnmarshalJSON decodes a list of names, a string in any form Parse
+// accepts, or a number.
+func (f *Flags[T]) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err == nil {
+		g, err := Parse[T](strings.Join(names, "|"))
+		if err != nil {
+			return err
+		}
+		*f = g
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		g, err := Parse[T](s)
+		if err != nil {
+			return err
+		}
+		*f = g
+		return nil
+	}
+	var n uint64
+	if err := json.Unmarshal(data, &n); err != nil || n&^mask[T]() != 0 {
+		return fmt.Errorf("%w %s: want names, a string or a %s", ErrSyntax, data, reflect.TypeFor[T]())
+	}
+	*f = Flags[T]{T(n)}
+	return nil
+}
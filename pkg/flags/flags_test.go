@@ -0,0 +1,155 @@
+package flags
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// perm registers flags as the lesson numbers them: read 1, write 2,
+// execute 4.
+type perm uint8
+
+var (
+	read    = Register[perm]("read", 'r')
+	write   = Register[perm]("write", 'w')
+	execute = Register[perm]("execute", 'x')
+)
+
+// mode numbers them as Unix does: read 4, write 2, execute 1.
+type mode uint16
+
+var (
+	modeRead    = Define[mode](4, "read", 'r')
+	modeWrite   = Define[mode](2, "write", 'w')
+	modeExecute = Define[mode](1, "execute", 'x')
+)
+
+func TestLessonPermissions(t *testing.T) {
+	user1 := read.Set(execute)
+	user2 := read.Set(write)
+	user3 := All[perm]()
+	for _, tt := range []struct {
+		f    Flags[perm]
+		bits perm
+		str  string
+	}{
+		{user1, 5, "r-x"},
+		{user2, 3, "rw-"},
+		{user3, 7, "rwx"},
+		{Flags[perm]{}, 0, "---"},
+		{Of[perm](0o205), 0o205, "r-x+0o200"},
+	} {
+		if tt.f.Bits() != tt.bits || tt.f.String() != tt.str {
+			t.Errorf("got %d %q, want %d %q", tt.f.Bits(), tt.f, tt.bits, tt.str)
+		}
+	}
+	if !user1.Has(read) || user1.Has(write) || user1.Has(Flags[perm]{}) {
+		t.Errorf("%v: Has(read), Has(write), Has(none) = %v, %v, %v", user1, user1.Has(read), user1.Has(write), user1.Has(Flags[perm]{}))
+	}
+	if !user1.HasAll(read, execute) || user1.HasAll(read, write) || !user1.HasAll() {
+		t.Errorf("%v: HasAll wrong", user1)
+	}
+	if !user1.HasAny(write, execute) || user1.HasAny(write) || user1.HasAny() {
+		t.Errorf("%v: HasAny wrong", user1)
+	}
+	if got := user3.Clear(write); got != user1 {
+		t.Errorf("rwx.Clear(w) = %v, want %v", got, user1)
+	}
+	if got := user1.Toggle(read, write); got.String() != "-wx" {
+		t.Errorf("r-x.Toggle(r, w) = %v, want -wx", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want perm
+	}{
+		{"r-x", 5}, {"rwx", 7}, {"---", 0}, {"", 0},
+		{"read|execute", 5}, {"write, read", 3}, {"execute", 4},
+		{"5", 5}, {"0o7", 7}, {"06", 6},
+		{"r-x+0o200", 0o205}, {"---+0o10", 0o10},
+	} {
+		got, err := Parse[perm](tt.in)
+		if err != nil || got.Bits() != tt.want {
+			t.Errorf("Parse(%q) = %v, %v; want %d", tt.in, got.Bits(), err, tt.want)
+		}
+	}
+	for _, in := range []string{"rxw", "r-", "read|delete", "8", "0o400",
+		"r-x+", "r-x+200", "r-x+0o0", "r-x+0o9", "r-+0o200", "read+0o200", "r-x+0o4", "r-x+0o400"} {
+		if _, err := Parse[perm](in); !errors.Is(err, ErrSyntax) {
+			t.Errorf("Parse(%q): err = %v, want ErrSyntax", in, err)
+		}
+	}
+
+	// String and Parse round-trip every value, stray bits included.
+	for b := range 256 {
+		f := Of[perm](perm(b))
+		got, err := Parse[perm](f.String())
+		if err != nil || got != f {
+			t.Errorf("Parse(%q) = %v, %v; want %v", f.String(), got, err, f)
+		}
+	}
+
+	// Unix numbering: 6 is rw-.
+	if got, err := Parse[mode]("6"); err != nil || got != modeRead.Set(modeWrite) || got.String() != "rw-" {
+		t.Errorf("Parse[mode](6) = %v, %v; want rw-", got, err)
+	}
+	if got := modeRead.Set(modeExecute).Octal(); got != "5" {
+		t.Errorf("r-x.Octal() = %s, want 5", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type user struct {
+		Name string      `json:"name"`
+		Perm Flags[perm] `json:"perm"`
+	}
+	data, err := json.Marshal(user{"ana", read.Set(execute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"name":"ana","perm":["read","execute"]}`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+	for _, in := range []string{`["read","execute"]`, `"r-x"`, `"read|execute"`, `"5"`, `5`} {
+		var u user
+		if err := json.Unmarshal([]byte(`{"perm":`+in+`}`), &u); err != nil || u.Perm != read.Set(execute) {
+			t.Errorf("Unmarshal(%s) = %v, %v; want r-x", in, u.Perm, err)
+		}
+	}
+	if _, err := json.Marshal(Of[perm](0o10)); err == nil {
+		t.Error("Marshal of an unregistered bit succeeded")
+	}
+	var f Flags[perm]
+	if err := json.Unmarshal([]byte(`256`), &f); !errors.Is(err, ErrSyntax) {
+		t.Errorf("Unmarshal(256): err = %v, want ErrSyntax", err)
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	type small uint8
+	for i := range 8 {
+		Register[small](string(rune('a'+i)), rune('a'+i))
+	}
+	for name, register := range map[string]func(){
+		"full":         func() { Register[small]("i", 'i') },
+		"same name":    func() { Define[perm](8, "read", 'R') },
+		"same char":    func() { Define[perm](8, "rename", 'r') },
+		"same bit":     func() { Define[perm](1, "run", 'u') },
+		"two bits":     func() { Define[perm](24, "other", 'o') },
+		"reserved":     func() { Define[perm](8, "other", '-') },
+		"reserved +":   func() { Define[perm](8, "other", '+') },
+		"invalid name": func() { Define[perm](8, "a|b", 'o') },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: no panic", name)
+				}
+			}()
+			register()
+		}()
+	}
+}
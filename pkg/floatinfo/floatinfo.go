@@ -0,0 +1,212 @@
+// Package floatinfo takes IEEE 754 floating-point values apart: the sign,
+// exponent and mantissa bits, the exact decimal value actually stored, the
+// gap to the next representable value (ULP) and the neighbours on either
+// side, and what is lost when a float64 is narrowed to float32.
+//
+// It backs the float32 vs float64 part of Day 4, where pi printed with
+// %.15f differs after the seventh digit in float32.
+package floatinfo
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Format describes a binary floating-point format.
+type Format struct {
+	Name     string `json:"name"`
+	Bits     int    `json:"bits"`
+	ExpBits  int    `json:"exp_bits"`
+	MantBits int    `json:"mant_bits"` // stored fraction bits, without the implicit leading 1
+	Bias     int    `json:"bias"`
+}
+
+var (
+	Float32 = Format{Name: "float32", Bits: 32, ExpBits: 8, MantBits: 23, Bias: 127}
+	Float64 = Format{Name: "float64", Bits: 64, ExpBits: 11, MantBits: 52, Bias: 1023}
+)
+
+// Class is the IEEE 754 category of a value.
+type Class string
+
+const (
+	Zero      Class = "zero"
+	Subnormal Class = "subnormal"
+	Normal    Class = "normal"
+	Infinity  Class = "infinity"
+	NaN       Class = "NaN"
+)
+
+// Value is a decomposed floating-point value.
+type Value struct {
+	Format   Format  `json:"format"`
+	Float    float64 `json:"value"` // float32 values are widened, which is exact
+	Bits     uint64  `json:"bits"`
+	Sign     uint64  `json:"sign"`     // 1 for negative
+	Exponent uint64  `json:"exponent"` // biased exponent field
+	Mantissa uint64  `json:"mantissa"` // fraction field
+	Class    Class   `json:"class"`
+
+	// Power is the power of two the significand is scaled by: the
+	// unbiased exponent for normal values, 1-bias for subnormal ones.
+	Power int `json:"power"`
+
+	Exact string  `json:"exact"`          // every decimal digit of the stored value
+	ULP   float64 `json:"ulp"`            // distance to the next value away from zero
+	Prev  float64 `json:"prev"`           // largest value below this one
+	Next  float64 `json:"next"`           // smallest value above this one
+	Note  string  `json:"note,omitempty"` // set by Parse when the input is not stored exactly
+}
+
+// Of64 decomposes a float64.
+func Of64(f float64) Value {
+	v := decompose(Float64, math.Float64bits(f))
+	v.Float = f
+	if !math.IsNaN(f) {
+		v.Prev, v.Next = math.Nextafter(f, math.Inf(-1)), math.Nextafter(f, math.Inf(1))
+		v.ULP = ulp(f, math.Nextafter(math.Abs(f), math.Inf(1)), math.Nextafter(math.Abs(f), 0))
+	}
+	v.Exact = exact(f)
+	return v
+}
+
+// Of32 decomposes a float32.
+func Of32(f float32) Value {
+	v := decompose(Float32, uint64(math.Float32bits(f)))
+	v.Float = float64(f)
+	if !math.IsNaN(v.Float) {
+		abs := float32(math.Abs(float64(f)))
+		v.Prev = float64(math.Nextafter32(f, float32(math.Inf(-1))))
+		v.Next = float64(math.Nextafter32(f, float32(math.Inf(1))))
+		v.ULP = ulp(float64(abs), float64(math.Nextafter32(abs, float32(math.Inf(1)))), float64(math.Nextafter32(abs, 0)))
+	}
+	v.Exact = exact(v.Float)
+	return v
+}
+
+// ulp is the gap above abs, or below it at the largest finite value.
+func ulp(abs, up, down float64) float64 {
+	switch {
+	case math.IsInf(abs, 0):
+		return math.Inf(1)
+	case math.IsInf(up, 0):
+		return abs - down
+	}
+	return up - abs
+}
+
+func decompose(f Format, bits uint64) Value {
+	v := Value{
+		Format:   f,
+		Bits:     bits,
+		Sign:     bits >> (f.Bits - 1),
+		Exponent: bits >> f.MantBits & (1<<f.ExpBits - 1),
+		Mantissa: bits & (1<<f.MantBits - 1),
+	}
+	switch {
+	case v.Exponent == 1<<f.ExpBits-1 && v.Mantissa == 0:
+		v.Class = Infinity
+	case v.Exponent == 1<<f.ExpBits-1:
+		v.Class = NaN
+	case v.Exponent == 0 && v.Mantissa == 0:
+		v.Class = Zero
+	case v.Exponent == 0:
+		v.Class, v.Power = Subnormal, 1-f.Bias
+	default:
+		v.Class, v.Power = Normal, int(v.Exponent)-f.Bias
+	}
+	return v
+}
+
+// exact returns the full decimal expansion of f. Every finite binary
+// fraction has one: a value with k fraction bits has exactly k decimals.
+func exact(f float64) string {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return fmt.Sprint(f)
+	}
+	r := new(big.Rat).SetFloat64(f)
+	s := r.FloatString(r.Denom().BitLen() - 1)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if math.Signbit(f) && !strings.HasPrefix(s, "-") {
+		s = "-" + s // -0
+	}
+	return s
+}
+
+// BitString returns the bits grouped as sign, exponent and mantissa,
+// e.g. "0 10000000 10010010000111111011011" for float32(pi).
+func (v Value) BitString() string {
+	s := fmt.Sprintf("%0*b", v.Format.Bits, v.Bits)
+	return s[:1] + " " + s[1:1+v.Format.ExpBits] + " " + s[1+v.Format.ExpBits:]
+}
+
+// Formula spells out how the bits make the value, e.g.
+// "+1.5707963705062866 × 2^1" for float32(pi).
+func (v Value) Formula() string {
+	sign := "+"
+	if v.Sign == 1 {
+		sign = "-"
+	}
+	frac := strconv.FormatFloat(float64(v.Mantissa)/float64(uint64(1)<<v.Format.MantBits), 'f', -1, 64)
+	frac = strings.TrimPrefix(frac, "0")
+	switch v.Class {
+	case Normal:
+		return fmt.Sprintf("%s1%s × 2^%d", sign, frac, v.Power)
+	case Subnormal:
+		return fmt.Sprintf("%s0%s × 2^%d", sign, frac, v.Power)
+	}
+	return sign + string(v.Class)
+}
+
+// Narrowing is the result of converting a float64 to float32 and back.
+type Narrowing struct {
+	From     float64 `json:"from"`
+	To       float32 `json:"to"`
+	Back     float64 `json:"back"`      // float64(To)
+	AbsError float64 `json:"abs_error"` // Back - From
+	RelError float64 `json:"rel_error"` // AbsError / From, 0 when From is 0
+	Exact    bool    `json:"exact"`
+}
+
+// Narrow converts f to float32 and measures the round-trip error.
+func Narrow(f float64) Narrowing {
+	n := Narrowing{From: f, To: float32(f)}
+	n.Back = float64(n.To)
+	n.Exact = n.Back == f || math.IsNaN(f) && math.IsNaN(n.Back)
+	if !n.Exact {
+		n.AbsError = n.Back - f
+		if f != 0 && !math.IsInf(n.AbsError, 0) {
+			n.RelError = n.AbsError / f
+		}
+	}
+	return n
+}
+
+// WriteText writes the decomposition of v, one property per line, each
+// indented by indent.
+func (v Value) WriteText(w io.Writer, indent string) error {
+	fmt.Fprintf(w, "%sbits      %s\n", indent, v.BitString())
+	fmt.Fprintf(w, "%s          sign %d, exponent %d, mantissa %#x (%s)\n", indent, v.Sign, v.Exponent, v.Mantissa, v.Class)
+	fmt.Fprintf(w, "%svalue     %s\n", indent, v.Formula())
+	fmt.Fprintf(w, "%sexact     %s\n", indent, v.Exact)
+	if v.Class != NaN {
+		fmt.Fprintf(w, "%sulp       %s\n", indent, v.format(v.ULP))
+		fmt.Fprintf(w, "%sprev      %s\n", indent, v.format(v.Prev))
+		fmt.Fprintf(w, "%snext      %s\n", indent, v.format(v.Next))
+	}
+	if v.Note != "" {
+		fmt.Fprintf(w, "%snote      %s\n", indent, v.Note)
+	}
+	return nil
+}
+
+// format prints f with the shortest digits that identify it in v's format.
+func (v Value) format(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, v.Format.Bits)
+}
@@ -0,0 +1,71 @@
+package floatinfo
+
+import (
+	"math"
+	"testing"
+)
+
+// The formats agree with the limits in package math.
+func TestFormats(t *testing.T) {
+	for _, tt := range []struct {
+		f                       Format
+		max, smallest, smallNor float64
+	}{
+		{Float32, math.MaxFloat32, math.SmallestNonzeroFloat32, 0x1p-126},
+		{Float64, math.MaxFloat64, math.SmallestNonzeroFloat64, 0x1p-1022},
+	} {
+		f := tt.f
+		if f.Bits != 1+f.ExpBits+f.MantBits || f.Bias != 1<<(f.ExpBits-1)-1 {
+			t.Errorf("%s: %d bits, %d exponent, %d mantissa, bias %d do not add up", f.Name, f.Bits, f.ExpBits, f.MantBits, f.Bias)
+		}
+		if max := (2 - math.Ldexp(1, -f.MantBits)) * math.Ldexp(1, f.Bias); max != tt.max {
+			t.Errorf("%s: largest value %g, want %g", f.Name, max, tt.max)
+		}
+		if smallest := math.Ldexp(1, 1-f.Bias-f.MantBits); smallest != tt.smallest {
+			t.Errorf("%s: smallest value %g, want %g", f.Name, smallest, tt.smallest)
+		}
+		if normal := math.Ldexp(1, 1-f.Bias); normal != tt.smallNor {
+			t.Errorf("%s: smallest normal value %g, want %g", f.Name, normal, tt.smallNor)
+		}
+	}
+}
+
+// The limits decompose into the bit patterns IEEE 754 defines for them.
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name               string
+		v                  Value
+		class              Class
+		exponent, mantissa uint64
+		power              int
+		ulp, next          float64
+	}{
+		{"MaxFloat64", Of64(math.MaxFloat64), Normal, 0x7fe, 1<<52 - 1, 1023, 0x1p971, math.Inf(1)},
+		{"SmallestNonzeroFloat64", Of64(math.SmallestNonzeroFloat64), Subnormal, 0, 1, -1022, math.SmallestNonzeroFloat64, 2 * math.SmallestNonzeroFloat64},
+		{"MaxFloat32", Of32(math.MaxFloat32), Normal, 0xfe, 1<<23 - 1, 127, 0x1p104, math.Inf(1)},
+		{"SmallestNonzeroFloat32", Of32(math.SmallestNonzeroFloat32), Subnormal, 0, 1, -126, math.SmallestNonzeroFloat32, 2 * math.SmallestNonzeroFloat32},
+		{"1.0", Of64(1), Normal, 1023, 0, 0, 0x1p-52, 1 + 0x1p-52},
+		{"float32 1.0", Of32(1), Normal, 127, 0, 0, 0x1p-23, 1 + 0x1p-23},
+		{"-0", Of64(math.Copysign(0, -1)), Zero, 0, 0, 0, math.SmallestNonzeroFloat64, math.SmallestNonzeroFloat64},
+		{"+Inf", Of64(math.Inf(1)), Infinity, 0x7ff, 0, 0, math.Inf(1), math.Inf(1)},
+	}
+	for _, tt := range tests {
+		v := tt.v
+		if v.Class != tt.class || v.Exponent != tt.exponent || v.Mantissa != tt.mantissa || v.Power != tt.power {
+			t.Errorf("%s: class %s, exponent %#x, mantissa %#x, power %d; want %s, %#x, %#x, %d",
+				tt.name, v.Class, v.Exponent, v.Mantissa, v.Power, tt.class, tt.exponent, tt.mantissa, tt.power)
+		}
+		if v.ULP != tt.ulp || v.Next != tt.next {
+			t.Errorf("%s: ULP %g, next %g; want %g, %g", tt.name, v.ULP, v.Next, tt.ulp, tt.next)
+		}
+	}
+	if v := Of64(math.Copysign(0, -1)); v.Sign != 1 {
+		t.Errorf("-0: sign %d, want 1", v.Sign)
+	}
+	if v := Of64(math.NaN()); v.Class != NaN {
+		t.Errorf("NaN: class %s", v.Class)
+	}
+	if v := Of32(math.MaxFloat32); v.Exact != "340282346638528859811704183484516925440" {
+		t.Errorf("MaxFloat32: exact %s", v.Exact)
+	}
+}
@@ -0,0 +1,49 @@
+package floatinfo
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Parse reads a decimal literal such as "0.1" or "6.022e23", or a
+// quotient such as "1/3", and returns the nearest float64 and the nearest
+// float32. Both are rounded once from the exact input, as the compiler
+// rounds a constant. When the input cannot be stored exactly, Note says
+// so. "Inf", "-Inf" and "NaN" are accepted too.
+func Parse(s string) (f64, f32 Value, err error) {
+	s = strings.TrimSpace(s)
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return Value{}, Value{}, fmt.Errorf("%q is not a number or a quotient a/b", s)
+		}
+		return Of64(f), Of32(float32(f)), nil
+	}
+	x64, exact64 := r.Float64()
+	x32, exact32 := r.Float32()
+	if r.Sign() == 0 && strings.HasPrefix(s, "-") {
+		x64, x32 = math.Copysign(0, -1), float32(math.Copysign(0, -1)) // big.Rat has no -0
+	}
+	f64, f32 = Of64(x64), Of32(x32)
+	if !exact64 {
+		f64.Note = inexact(s, r, f64)
+	}
+	if !exact32 {
+		f32.Note = inexact(s, r, f32)
+	}
+	return f64, f32, nil
+}
+
+func inexact(input string, want *big.Rat, v Value) string {
+	got, ok := new(big.Rat).SetString(v.Exact)
+	if !ok {
+		return fmt.Sprintf("%s overflows %s", input, v.Format.Name)
+	}
+	diff := new(big.Rat).Sub(got, want)
+	d, _ := diff.Float64()
+	return fmt.Sprintf("%s is not exactly representable in %s; the stored value is off by %.3g", input, v.Format.Name, d)
+}
@@ -0,0 +1,109 @@
+package floatinfo
+
+import (
+	"fmt"
+	"math"
+)
+
+// Pitfall is a classic floating-point surprise, evaluated at run time so
+// the result is what the hardware does, not what constant arithmetic in
+// the compiler would give.
+type Pitfall struct {
+	Name    string `json:"name"`
+	Expr    string `json:"expr"`
+	Result  string `json:"result"`
+	Naive   string `json:"naive"` // what most people expect
+	Explain string `json:"explain"`
+}
+
+// Surprising reports whether the result differs from the naive
+// expectation, which it does on every IEEE 754 machine.
+func (p Pitfall) Surprising() bool {
+	return p.Result != p.Naive
+}
+
+// Variables keep the compiler from folding the expressions: Go constants
+// are exact, so the constant expression 0.1+0.2 == 0.3 is true.
+var (
+	pointOne, pointTwo, pointThree = 0.1, 0.2, 0.3
+	one, three                     = 1.0, 3.0
+	big16, two53                   = 1e16, float64(1 << 53)
+	zero                           = 0.0
+)
+
+// Pitfalls returns the catalogue of classic floating-point pitfalls.
+func Pitfalls() []Pitfall {
+	sum := 0.0
+	for i := 0; i < 10; i++ {
+		sum += pointOne
+	}
+	negZero := math.Copysign(0, -1)
+	nan := math.NaN()
+	f32 := float32(pointOne)
+
+	return []Pitfall{
+		{
+			Name:    "0.1 + 0.2 != 0.3",
+			Expr:    "a, b, c := 0.1, 0.2, 0.3; a+b == c",
+			Result:  fmt.Sprint(pointOne+pointTwo == pointThree),
+			Naive:   "true",
+			Explain: fmt.Sprintf("none of the three is exact in binary; a+b is %v. Compare with a tolerance: math.Abs(a+b-c) < 1e-9", pointOne+pointTwo),
+		},
+		{
+			Name:    "adding 0.1 ten times",
+			Expr:    "sum := 0.0; for range 10 { sum += 0.1 }; sum == 1",
+			Result:  fmt.Sprint(sum == 1),
+			Naive:   "true",
+			Explain: fmt.Sprintf("each addition rounds, and the errors accumulate: sum is %v", sum),
+		},
+		{
+			Name:    "float32 is not float64",
+			Expr:    "float64(float32(0.1)) == 0.1",
+			Result:  fmt.Sprint(float64(f32) == pointOne),
+			Naive:   "true",
+			Explain: fmt.Sprintf("float32(0.1) keeps 24 significant bits; widened it is %v", float64(f32)),
+		},
+		{
+			Name:    "large + small is absorbed",
+			Expr:    "x := 1e16; x+1 == x",
+			Result:  fmt.Sprint(big16+1 == big16),
+			Naive:   "false",
+			Explain: "at 1e16 consecutive float64 values are 2 apart, so adding 1 rounds back to x",
+		},
+		{
+			Name:    "integers above 2^53",
+			Expr:    "x := float64(1 << 53); x+1 == x",
+			Result:  fmt.Sprint(two53+1 == two53),
+			Naive:   "false",
+			Explain: "float64 has 53 significant bits; 9007199254740993 has no float64 of its own (float32 runs out at 2^24)",
+		},
+		{
+			Name:    "float32 runs out of digits",
+			Expr:    `fmt.Sprintf("%.10f vs %.10f", float32(1)/3, 1.0/3)`,
+			Result:  fmt.Sprintf("%.10f vs %.10f", float32(one)/float32(three), one/three),
+			Naive:   "0.3333333333 vs 0.3333333333",
+			Explain: "float32 carries about 7 significant decimal digits, float64 about 15-16",
+		},
+		{
+			Name:    "NaN is not equal to itself",
+			Expr:    "x := math.NaN(); x == x",
+			Result:  fmt.Sprint(nan == nan),
+			Naive:   "true",
+			Explain: "every comparison with NaN is false; test with math.IsNaN, and never use NaN as a map key",
+		},
+		{
+			Name:    "negative zero",
+			Expr:    "z := math.Copysign(0, -1); z == 0, 1/z",
+			Result:  fmt.Sprint(negZero == 0, ", ", 1/negZero),
+			Naive:   "true, +Inf",
+			Explain: "-0 compares equal to 0 but keeps its sign bit; math.Signbit tells them apart",
+		},
+		{
+			Name:    "division by zero does not panic",
+			Expr:    "x := 0.0; 1/x",
+			Result:  fmt.Sprint(one / zero),
+			Naive:   "panic",
+			Explain: "float division by zero gives ±Inf or NaN; only integer division by zero panics",
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package layout_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+
+	"github.com/ALS240/GoTrainings/pkg/layout"
+)
+
+// MixedStruct from the escape analysis lesson (Codes/Day4/07_EscapeAnalysis)
+// has only word-aligned fields, so it has no padding anywhere. Adding a
+// bool flag in front and a counter in the middle, as code tends to grow,
+// costs 11 bytes of padding on amd64; reordering wins back 8 of them.
+const src = `package lesson
+
+type MixedStruct struct {
+	Name string
+	Data []int
+	Ref  *string
+}
+
+type GrownMixedStruct struct {
+	Active bool
+	Name   string
+	Count  int32
+	Data   []int
+	Ref    *string
+}
+`
+
+func Example() {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "lesson.go", src, 0)
+	if err != nil {
+		panic(err)
+	}
+	pkg, err := new(types.Config).Check("lesson", fset, []*ast.File{f}, nil)
+	if err != nil {
+		panic(err)
+	}
+	for _, d := range layout.Structs(fset, pkg) {
+		s, err := layout.FromTypes(d.Name, d.Struct, "amd64")
+		if err != nil {
+			panic(err)
+		}
+		s.WriteText(os.Stdout)
+		s.WriteSuggestion(os.Stdout)
+	}
+	// Output:
+	// MixedStruct (amd64): size 48, align 8, padding 0
+	//     offset  size  align  field
+	//          0    16      8  Name string
+	//         16    24      8  Data []int
+	//         40     8      8  Ref *string
+	// MixedStruct (amd64): field order is already optimal
+	// GrownMixedStruct (amd64): size 64, align 8, padding 11
+	//     offset  size  align  field
+	//          0     1      1  Active bool
+	//          1     7         (padding)
+	//          8    16      8  Name string
+	//         24     4      4  Count int32
+	//         28     4         (padding)
+	//         32    24      8  Data []int
+	//         56     8      8  Ref *string
+	// GrownMixedStruct (amd64): reordering saves 8 bytes (64 -> 56):
+	// type GrownMixedStruct struct {
+	// 	Data   []int
+	// 	Name   string
+	// 	Ref    *string
+	// 	Count  int32
+	// 	Active bool
+	// }
+}
@@ -0,0 +1,190 @@
+// Package layout reports how the gc compiler lays out a struct in memory:
+// each field's offset, size and alignment, the padding inserted after it,
+// and a field order that needs less padding.
+//
+// Layouts come either from a live type through reflect (Of, for the
+// architecture the program runs on) or from type-checked source through
+// go/types (FromTypes, for any architecture gc supports).
+package layout
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"runtime"
+	"sort"
+)
+
+// Arches are the architectures reported by default.
+var Arches = []string{"amd64", "386", "arm64"}
+
+// Field is one field of a struct layout.
+type Field struct {
+	Name     string `json:"name"` // type name for an embedded field
+	Type     string `json:"type"`
+	Offset   int64  `json:"offset"`
+	Size     int64  `json:"size"`
+	Align    int64  `json:"align"`
+	Padding  int64  `json:"padding"` // bytes between the end of this field and the next field or the end of the struct
+	Embedded bool   `json:"embedded,omitempty"`
+}
+
+// Struct is the layout of one struct type on one architecture.
+type Struct struct {
+	Name    string  `json:"name"`
+	Arch    string  `json:"arch"`
+	Size    int64   `json:"size"`
+	Align   int64   `json:"align"`
+	Padding int64   `json:"padding"` // total padding bytes
+	Fields  []Field `json:"fields"`
+}
+
+// Of returns the layout of t, which must be a struct type, on the running
+// architecture.
+func Of(t reflect.Type) (*Struct, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("layout: %s is a %s, not a struct", t, t.Kind())
+	}
+	s := &Struct{Name: t.Name(), Arch: runtime.GOARCH, Size: int64(t.Size()), Align: int64(t.Align())}
+	if s.Name == "" {
+		s.Name = t.String()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		s.Fields = append(s.Fields, Field{
+			Name:     f.Name,
+			Type:     f.Type.String(),
+			Offset:   int64(f.Offset),
+			Size:     int64(f.Type.Size()),
+			Align:    int64(f.Type.FieldAlign()),
+			Embedded: f.Anonymous,
+		})
+	}
+	s.fillPadding()
+	return s, nil
+}
+
+// FromTypes returns the layout of st, named name, on arch as gc would lay
+// it out. A struct whose layout depends on a type parameter is an error.
+func FromTypes(name string, st *types.Struct, arch string) (*Struct, error) {
+	sizes := types.SizesFor("gc", arch)
+	if sizes == nil {
+		return nil, fmt.Errorf("layout: unknown architecture %q", arch)
+	}
+	if dependsOnTypeParam(st) {
+		return nil, fmt.Errorf("layout: %s is generic; its layout depends on the type arguments", name)
+	}
+	vars := make([]*types.Var, st.NumFields())
+	for i := range vars {
+		vars[i] = st.Field(i)
+	}
+	offsets := sizes.Offsetsof(vars)
+	s := &Struct{Name: name, Arch: arch, Size: sizes.Sizeof(st), Align: sizes.Alignof(st)}
+	for i, v := range vars {
+		s.Fields = append(s.Fields, Field{
+			Name:     v.Name(),
+			Type:     types.TypeString(v.Type(), types.RelativeTo(v.Pkg())),
+			Offset:   offsets[i],
+			Size:     sizes.Sizeof(v.Type()),
+			Align:    sizes.Alignof(v.Type()),
+			Embedded: v.Embedded(),
+		})
+	}
+	s.fillPadding()
+	return s, nil
+}
+
+// dependsOnTypeParam reports whether the size of t is unknown until a type
+// parameter is instantiated. go/types panics when asked for such a size.
+func dependsOnTypeParam(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Array:
+		return dependsOnTypeParam(t.Elem())
+	case *types.Named:
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			if dependsOnTypeParam(t.TypeArgs().At(i)) {
+				return true
+			}
+		}
+		return false
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if dependsOnTypeParam(t.Field(i).Type()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fillPadding sets each field's padding and the total from the offsets.
+func (s *Struct) fillPadding() {
+	s.Padding = 0
+	for i := range s.Fields {
+		f := &s.Fields[i]
+		next := s.Size
+		if i+1 < len(s.Fields) {
+			next = s.Fields[i+1].Offset
+		}
+		f.Padding = next - f.Offset - f.Size
+		s.Padding += f.Padding
+	}
+	if len(s.Fields) == 0 {
+		s.Padding = s.Size
+	}
+}
+
+// Optimized returns s with its fields reordered to minimise padding:
+// alignment descending, then size descending, keeping the original order
+// among equals. Zero-size fields go first, since a trailing one costs
+// padding of its own. The result is recomputed with gc's layout rules.
+func (s *Struct) Optimized() *Struct {
+	fields := append([]Field(nil), s.Fields...)
+	sort.SliceStable(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if (a.Size == 0) != (b.Size == 0) {
+			return a.Size == 0
+		}
+		if a.Align != b.Align {
+			return a.Align > b.Align
+		}
+		return a.Size > b.Size
+	})
+	return s.Reorder(fields)
+}
+
+// Reorder returns s laid out with its fields in the given order, which
+// must be a permutation of s.Fields.
+func (s *Struct) Reorder(fields []Field) *Struct {
+	out := &Struct{Name: s.Name, Arch: s.Arch, Align: 1}
+	var off int64
+	for _, f := range fields {
+		off = alignUp(off, f.Align)
+		f.Offset = off
+		off += f.Size
+		out.Align = max(out.Align, f.Align)
+		out.Fields = append(out.Fields, f)
+	}
+	// gc pads a trailing zero-size field so that taking its address
+	// cannot point past the end of the struct.
+	if n := len(fields); n > 0 && fields[n-1].Size == 0 && off > 0 {
+		off++
+	}
+	out.Size = alignUp(off, out.Align)
+	out.fillPadding()
+	return out
+}
+
+func alignUp(n, align int64) int64 {
+	if align <= 1 {
+		return n
+	}
+	return (n + align - 1) / align * align
+}
+
+// Optimal reports whether no reordering of the fields needs less padding.
+func (s *Struct) Optimal() bool {
+	return s.Optimized().Size >= s.Size
+}
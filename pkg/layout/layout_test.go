@@ -0,0 +1,187 @@
+package layout
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// The types below are declared twice, in Go for Of and in src for
+// FromTypes, so the two paths can be compared.
+type (
+	empty  struct{}
+	padded struct {
+		A bool
+		B int64
+		C bool
+	}
+	embedded struct {
+		padded
+		S string
+		U uint16
+	}
+	trailing struct {
+		N int32
+		Z struct{}
+	}
+	mixed struct {
+		B   byte
+		F   float32
+		P   unsafe.Pointer
+		Arr [3]int16
+		M   map[string]int
+		I   any
+		C   complex128
+	}
+)
+
+const src = `package layout
+
+import "unsafe"
+
+type (
+	empty    struct{}
+	padded   struct {
+		A bool
+		B int64
+		C bool
+	}
+	embedded struct {
+		padded
+		S string
+		U uint16
+	}
+	trailing struct {
+		N int32
+		Z struct{}
+	}
+	mixed struct {
+		B   byte
+		F   float32
+		P   unsafe.Pointer
+		Arr [3]int16
+		M   map[string]int
+		I   any
+		C   complex128
+	}
+	generic[T any] struct {
+		A bool
+		X T
+	}
+)
+`
+
+func check(t *testing.T, src string) (*token.FileSet, *types.Package) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "src.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types.Config{Importer: importer.Default()}
+	pkg, err := conf.Check("layout", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fset, pkg
+}
+
+func TestOfMatchesFromTypes(t *testing.T) {
+	if runtime.GOARCH != "amd64" {
+		t.Skipf("compares with amd64 layouts, running on %s", runtime.GOARCH)
+	}
+	fset, pkg := check(t, src)
+	decls := map[string]*types.Struct{}
+	for _, d := range Structs(fset, pkg) {
+		decls[d.Name] = d.Struct
+	}
+	for _, typ := range []reflect.Type{
+		reflect.TypeFor[empty](),
+		reflect.TypeFor[padded](),
+		reflect.TypeFor[embedded](),
+		reflect.TypeFor[trailing](),
+		reflect.TypeFor[mixed](),
+	} {
+		got, err := Of(typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := FromTypes(typ.Name(), decls[typ.Name()], "amd64")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Type strings differ between reflect ("layout.padded") and
+		// go/types ("padded"); the numbers must not.
+		for i := range got.Fields {
+			got.Fields[i].Type = ""
+		}
+		for i := range want.Fields {
+			want.Fields[i].Type = ""
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s:\nOf        %+v\nFromTypes %+v", typ.Name(), got, want)
+		}
+	}
+}
+
+func TestLayouts(t *testing.T) {
+	fset, pkg := check(t, src)
+	tests := []struct {
+		name, arch          string
+		size, align, padded int64
+	}{
+		{"empty", "amd64", 0, 1, 0},
+		{"padded", "amd64", 24, 8, 14},
+		{"padded", "386", 16, 4, 6},
+		{"trailing", "amd64", 8, 4, 4},  // a final zero-size field gets a byte, rounded up to 8
+		{"embedded", "arm64", 48, 8, 6}, // padding inside the embedded field is its own
+	}
+	decls := map[string]*types.Struct{}
+	for _, d := range Structs(fset, pkg) {
+		decls[d.Name] = d.Struct
+	}
+	for _, tt := range tests {
+		s, err := FromTypes(tt.name, decls[tt.name], tt.arch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Size != tt.size || s.Align != tt.align || s.Padding != tt.padded {
+			t.Errorf("%s on %s: size %d, align %d, padding %d; want %d, %d, %d",
+				tt.name, tt.arch, s.Size, s.Align, s.Padding, tt.size, tt.align, tt.padded)
+		}
+	}
+}
+
+func TestGeneric(t *testing.T) {
+	fset, pkg := check(t, src)
+	for _, d := range Structs(fset, pkg) {
+		if d.Name == "generic" {
+			t.Errorf("Structs returned the generic type %s", d.Name)
+		}
+	}
+
+	// A struct whose field is a type parameter, as found inside a
+	// generic declaration, is refused rather than sized.
+	g := pkg.Scope().Lookup("generic").Type().Underlying().(*types.Struct)
+	if _, err := FromTypes("generic", g, "amd64"); err == nil || !strings.Contains(err.Error(), "generic") {
+		t.Errorf("FromTypes(generic) err = %v, want a generic type error", err)
+	}
+
+	// An instantiation has a layout.
+	generic := pkg.Scope().Lookup("generic").Type()
+	named, err := types.Instantiate(nil, generic, []types.Type{types.Typ[types.Int]}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := FromTypes("generic[int]", named.Underlying().(*types.Struct), "amd64")
+	if err != nil || s.Size != 16 {
+		t.Errorf("FromTypes(generic[int]) = %v, %v, want size 16", s, err)
+	}
+}
@@ -0,0 +1,49 @@
+package layout
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// WriteText writes s as a table with one row per field and a row for
+// every run of padding bytes.
+func (s *Struct) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "%s (%s): size %d, align %d, padding %d\n", s.Name, s.Arch, s.Size, s.Align, s.Padding)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "  offset\tsize\talign\t  field")
+	for _, f := range s.Fields {
+		fmt.Fprintf(tw, "  %d\t%d\t%d\t  %s %s\n", f.Offset, f.Size, f.Align, f.Name, f.Type)
+		if f.Padding > 0 {
+			fmt.Fprintf(tw, "  %d\t%d\t\t  (padding)\n", f.Offset+f.Size, f.Padding)
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteSuggestion writes the reordered layout when it is smaller than s,
+// or a note that the order is already optimal.
+func (s *Struct) WriteSuggestion(w io.Writer) error {
+	o := s.Optimized()
+	if o.Size >= s.Size {
+		_, err := fmt.Fprintf(w, "%s (%s): field order is already optimal\n", s.Name, s.Arch)
+		return err
+	}
+	fmt.Fprintf(w, "%s (%s): reordering saves %d bytes (%d -> %d):\n", s.Name, s.Arch, s.Size-o.Size, s.Size, o.Size)
+	fmt.Fprintf(w, "type %s struct {\n", s.Name)
+	width := 0
+	for _, f := range o.Fields {
+		if !f.Embedded {
+			width = max(width, len(f.Name))
+		}
+	}
+	for _, f := range o.Fields {
+		if f.Embedded {
+			fmt.Fprintf(w, "\t%s\n", f.Type)
+			continue
+		}
+		fmt.Fprintf(w, "\t%-*s %s\n", width, f.Name, f.Type)
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
@@ -0,0 +1,95 @@
+package layout
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Decl is a struct type declared at package level in Go source.
+type Decl struct {
+	Name   string
+	Pos    token.Position
+	Struct *types.Struct
+}
+
+// Load type-checks a Go file, or the non-test files of the package in a
+// directory, and returns its package-level struct types in source order.
+func Load(path string) ([]Decl, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if fi.IsDir() {
+		matches, err := filepath.Glob(filepath.Join(path, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		files = files[:0]
+		for _, m := range matches {
+			if !strings.HasSuffix(m, "_test.go") {
+				files = append(files, m)
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no Go files in %s", path)
+		}
+	}
+
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for _, name := range files {
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check(parsed[0].Name.Name, fset, parsed, nil)
+	if err != nil {
+		return nil, err
+	}
+	return Structs(fset, pkg), nil
+}
+
+// Structs returns the package-level struct types of pkg in source order.
+// Generic types are skipped: they have no layout until instantiated.
+func Structs(fset *token.FileSet, pkg *types.Package) []Decl {
+	var decls []Decl
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		if named, ok := tn.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+			continue
+		}
+		st, ok := tn.Type().Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+		decls = append(decls, Decl{Name: name, Pos: fset.Position(tn.Pos()), Struct: st})
+	}
+	sortByPos(decls)
+	return decls
+}
+
+func sortByPos(decls []Decl) {
+	sort.Slice(decls, func(i, j int) bool {
+		a, b := decls[i].Pos, decls[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+}
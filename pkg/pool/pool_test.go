@@ -0,0 +1,209 @@
+package pool
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCounters(t *testing.T) {
+	made := 0
+	p := New(Options[*int]{Capacity: 2, New: func() *int { made++; return new(int) }})
+	a, b, c := p.Get(), p.Get(), p.Get() // three misses
+	p.Put(a)
+	p.Put(b)
+	p.Put(c)    // full: dropped
+	_ = p.Get() // hit
+	want := Stats{Hits: 1, Misses: 3, Drops: 1, Idle: 1}
+	if got := p.Stats(); got != want {
+		t.Errorf("Stats = %+v, want %+v", got, want)
+	}
+	if made != 3 {
+		t.Errorf("New called %d times, want 3", made)
+	}
+	if got := p.Stats().HitRate(); got != 0.25 {
+		t.Errorf("HitRate = %v, want 0.25", got)
+	}
+	if p.Cap() != 2 {
+		t.Errorf("Cap = %d, want 2", p.Cap())
+	}
+}
+
+func TestZeroOptions(t *testing.T) {
+	p := New(Options[[]byte]{})
+	if v := p.Get(); v != nil {
+		t.Errorf("Get without New = %v, want the zero value", v)
+	}
+	if p.Cap() != DefaultCapacity {
+		t.Errorf("Cap = %d, want DefaultCapacity", p.Cap())
+	}
+}
+
+func TestReset(t *testing.T) {
+	p := New(Options[[]byte]{
+		Reset: func(b []byte) bool {
+			clear(b)
+			return len(b) <= 4 // drop large buffers
+		},
+	})
+	p.Put([]byte("data"))
+	p.Put([]byte("too large"))
+	if got := p.Get(); string(got) != "\x00\x00\x00\x00" {
+		t.Errorf("Get after Reset = %q, want it cleared", got)
+	}
+	if st := p.Stats(); st.Drops != 1 || st.Idle != 0 {
+		t.Errorf("Stats = %+v, want 1 drop and nothing idle", st)
+	}
+}
+
+func TestSliceClasses(t *testing.T) {
+	s := NewSlices(SliceOptions[byte]{Sizes: []int{4096, 512, 512, 65536}})
+	if got := s.Sizes(); len(got) != 3 || got[0] != 512 || got[1] != 4096 || got[2] != 65536 {
+		t.Fatalf("Sizes = %v, want [512 4096 65536]", got)
+	}
+	for _, tt := range []struct{ n, cap int }{
+		{0, 512}, {1, 512}, {512, 512}, {513, 4096}, {65536, 65536}, {65537, 65537},
+	} {
+		b := s.Get(tt.n)
+		if len(b) != tt.n || cap(b) != tt.cap {
+			t.Errorf("Get(%d): len %d, cap %d; want %d, %d", tt.n, len(b), cap(b), tt.n, tt.cap)
+		}
+	}
+	if st := s.Stats(); st.Unpooled != 1 || st.Total().Misses != 5 {
+		t.Errorf("after Gets: Unpooled %d, misses %d; want 1 and 5", st.Unpooled, st.Total().Misses)
+	}
+
+	s.Put(make([]byte, 100))   // below the smallest class: dropped
+	s.Put(make([]byte, 5000))  // held by the 4096 class
+	s.Put(make([]byte, 1<<20)) // beyond the largest class: dropped
+	st := s.Stats()
+	if st.Unpooled != 3 {
+		t.Errorf("Unpooled = %d, want 3", st.Unpooled)
+	}
+	for _, c := range st.Classes {
+		want := 0
+		if c.Size == 4096 {
+			want = 1
+		}
+		if c.Idle != want {
+			t.Errorf("class %d: %d idle, want %d", c.Size, c.Idle, want)
+		}
+	}
+	if b := s.Get(1000); cap(b) != 5000 || s.Stats().Classes[1].Hits != 1 {
+		t.Errorf("Get(1000) after Put: cap %d, hits %d; want the 5000-capacity buffer back", cap(b), s.Stats().Classes[1].Hits)
+	}
+}
+
+func TestSliceReset(t *testing.T) {
+	s := NewSlices(SliceOptions[byte]{Sizes: []int{8}, Reset: func(b []byte) bool {
+		clear(b)
+		return true
+	}})
+	b := s.Get(3)
+	copy(b, "abc")
+	s.Put(b)
+	// Reset sees the slice at its class size, so the whole array is cleared.
+	if got := s.Get(8); string(got) != string(make([]byte, 8)) {
+		t.Errorf("Get after Put = %q, want zeros", got)
+	}
+}
+
+// Compare with:
+//
+//	go test -run '^$' -bench . -benchmem ./pkg/pool
+//	go run ./cmd/escapebench ./pkg/pool
+
+const bufSize = 4096
+
+var sink []byte
+
+func BenchmarkGetPut(b *testing.B) {
+	b.Run("make", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sink = make([]byte, bufSize)
+		}
+	})
+	b.Run("Pool", func(b *testing.B) {
+		p := New(Options[[]byte]{New: func() []byte { return make([]byte, bufSize) }})
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf := p.Get()
+			sink = buf
+			p.Put(buf)
+		}
+	})
+	b.Run("Slices", func(b *testing.B) {
+		p := NewSlices(SliceOptions[byte]{Sizes: []int{bufSize}})
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf := p.Get(bufSize)
+			sink = buf
+			p.Put(buf)
+		}
+	})
+	b.Run("sync.Pool", func(b *testing.B) {
+		// A pointer avoids allocating a slice header on every Put.
+		p := sync.Pool{New: func() any { buf := make([]byte, bufSize); return &buf }}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf := p.Get().(*[]byte)
+			sink = *buf
+			p.Put(buf)
+		}
+	})
+}
+
+func BenchmarkGetPutParallel(b *testing.B) {
+	b.Run("make", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			var buf []byte
+			for pb.Next() {
+				buf = make([]byte, bufSize)
+				buf[0] = 1
+			}
+		})
+	})
+	b.Run("Pool", func(b *testing.B) {
+		p := New(Options[[]byte]{New: func() []byte { return make([]byte, bufSize) }})
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				buf := p.Get()
+				buf[0] = 1
+				p.Put(buf)
+			}
+		})
+	})
+	b.Run("sync.Pool", func(b *testing.B) {
+		p := sync.Pool{New: func() any { buf := make([]byte, bufSize); return &buf }}
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				buf := p.Get().(*[]byte)
+				(*buf)[0] = 1
+				p.Put(buf)
+			}
+		})
+	})
+}
+
+// BenchmarkSizeClasses serves a mix of request sizes from one pool.
+func BenchmarkSizeClasses(b *testing.B) {
+	sizes := []int{100, 1000, 3000, 10000, 60000}
+	b.Run("make", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sink = make([]byte, sizes[i%len(sizes)])
+		}
+	})
+	b.Run("Slices", func(b *testing.B) {
+		p := NewSlices(SliceOptions[byte]{Sizes: []int{512, 4096, 65536}})
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf := p.Get(sizes[i%len(sizes)])
+			sink = buf
+			p.Put(buf)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package pool
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+)
+
+// SliceOptions configures a Slices pool.
+type SliceOptions[E any] struct {
+	// Sizes are the capacities of the size classes, e.g. 512, 4096, 65536.
+	// They need not be sorted. At least one size is required.
+	Sizes []int
+
+	// Capacity is the number of idle slices kept per class. Zero means
+	// DefaultCapacity.
+	Capacity int
+
+	// Reset, if set, is called by Put with the slice at its class size,
+	// e.g. clear to wipe sensitive data. Returning false drops the slice.
+	Reset func([]E) bool
+}
+
+// ClassStats are the counters of one size class.
+type ClassStats struct {
+	Size int `json:"size"`
+	Stats
+}
+
+// SliceStats is a snapshot of a Slices pool's counters.
+type SliceStats struct {
+	Classes []ClassStats `json:"classes"`
+
+	// Unpooled counts Gets larger than the largest class, which are
+	// allocated exactly, and Puts smaller than the smallest class or
+	// larger than the largest, which are dropped.
+	Unpooled uint64 `json:"unpooled"`
+}
+
+// Total adds up the counters of every class.
+func (s SliceStats) Total() Stats {
+	var t Stats
+	for _, c := range s.Classes {
+		t.Hits += c.Hits
+		t.Misses += c.Misses
+		t.Drops += c.Drops
+		t.Idle += c.Idle
+	}
+	return t
+}
+
+type sliceClass[E any] struct {
+	size int
+	pool *Pool[[]E]
+}
+
+// Slices pools []E in size classes: Get(n) is served by the smallest
+// class that holds n elements, so one pool can hand out both small and
+// large buffers without wasting a large buffer on a small request. It is
+// safe for concurrent use.
+type Slices[E any] struct {
+	classes  []sliceClass[E] // ascending by size
+	unpooled atomic.Uint64
+}
+
+// NewSlices returns an empty pool. It panics if opts.Sizes is empty or
+// holds a size that is not positive.
+func NewSlices[E any](opts SliceOptions[E]) *Slices[E] {
+	if len(opts.Sizes) == 0 {
+		panic("pool: NewSlices needs at least one size class")
+	}
+	sizes := append([]int(nil), opts.Sizes...)
+	sort.Ints(sizes)
+	s := &Slices[E]{}
+	for i, size := range sizes {
+		if size <= 0 {
+			panic(fmt.Sprintf("pool: invalid size class %d", size))
+		}
+		if i > 0 && size == sizes[i-1] {
+			continue
+		}
+		s.classes = append(s.classes, sliceClass[E]{
+			size: size,
+			pool: New(Options[[]E]{
+				Capacity: opts.Capacity,
+				New:      func() []E { return make([]E, size) },
+				Reset:    opts.Reset,
+			}),
+		})
+	}
+	return s
+}
+
+// Get returns a slice of length n. Its capacity is the size of the class
+// that served it, or exactly n if n is larger than every class. The
+// contents are whatever the previous user left unless Reset clears them.
+func (s *Slices[E]) Get(n int) []E {
+	if n < 0 {
+		panic("pool: negative length")
+	}
+	i := sort.Search(len(s.classes), func(i int) bool { return s.classes[i].size >= n })
+	if i == len(s.classes) {
+		s.unpooled.Add(1)
+		return make([]E, n)
+	}
+	return s.classes[i].pool.Get()[:n]
+}
+
+// Put returns b to the largest class it can hold. A slice with a capacity
+// beyond the largest class is dropped, so that the pool never keeps an
+// arbitrarily large array alive. The caller must not use b afterwards.
+func (s *Slices[E]) Put(b []E) {
+	i := sort.Search(len(s.classes), func(i int) bool { return s.classes[i].size > cap(b) }) - 1
+	if i < 0 || cap(b) > s.classes[len(s.classes)-1].size {
+		s.unpooled.Add(1)
+		return
+	}
+	c := s.classes[i]
+	c.pool.Put(b[:c.size])
+}
+
+// Sizes returns the class sizes in ascending order.
+func (s *Slices[E]) Sizes() []int {
+	sizes := make([]int, len(s.classes))
+	for i, c := range s.classes {
+		sizes[i] = c.size
+	}
+	return sizes
+}
+
+// Stats returns the counters of every class.
+func (s *Slices[E]) Stats() SliceStats {
+	st := SliceStats{Unpooled: s.unpooled.Load()}
+	for _, c := range s.classes {
+		st.Classes = append(st.Classes, ClassStats{Size: c.size, Stats: c.pool.Stats()})
+	}
+	return st
+}
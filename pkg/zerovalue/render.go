@@ -0,0 +1,38 @@
+package zerovalue
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WriteTree writes n and its children as an indented tree, one type per
+// line with its zero value, whether it is usable as-is, and why.
+func (n *Node) WriteTree(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	n.write(bw, "", "")
+	return bw.Flush()
+}
+
+func (n *Node) write(w *bufio.Writer, first, rest string) {
+	mark := "usable"
+	if !n.Usable {
+		mark = "NOT usable"
+	}
+	label := n.Name
+	if n.Name != n.Type {
+		label += " " + n.Type
+	}
+	fmt.Fprintf(w, "%s%s = %s  [%s]", first, label, n.Zero, mark)
+	if n.Note != "" {
+		fmt.Fprintf(w, " %s", n.Note)
+	}
+	fmt.Fprintln(w)
+	for i, c := range n.Children {
+		if i == len(n.Children)-1 {
+			c.write(w, rest+"└── ", rest+"    ")
+		} else {
+			c.write(w, rest+"├── ", rest+"│   ")
+		}
+	}
+}
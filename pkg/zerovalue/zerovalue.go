@@ -0,0 +1,220 @@
+// Package zerovalue explains the zero value of any Go type: what it is,
+// whether it is nil, and whether it can be used as-is or must be
+// initialised first. Structs, arrays, pointers, slices, maps and channels
+// are walked recursively, so a struct with a nil map field is reported as
+// not usable and the field that causes it is shown.
+//
+// Only map and channel fields make a struct's zero value unusable: a nil
+// pointer, func or interface field is typically optional, set later or
+// checked before use, as in http.Client. Unexported fields of types from
+// other packages are not walked; those are the package's own business,
+// and sync.Mutex or bytes.Buffer document their zero values as ready.
+package zerovalue
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Node describes the zero value of one type within a tree.
+type Node struct {
+	Name   string `json:"name"` // field name, "[i]" for array elements, "*" for a pointee, "key"/"elem" for maps and so on
+	Type   string `json:"type"`
+	Kind   string `json:"kind"`
+	Zero   string `json:"zero"` // the zero value as Go syntax
+	Nil    bool   `json:"nil"`
+	Usable bool   `json:"usable"` // whether the zero value can be used without initialisation
+	Note   string `json:"note,omitempty"`
+
+	// Children describe the parts of the type: the fields of a struct,
+	// the element of an array, and what a pointer, slice, map or channel
+	// would hold once initialised.
+	Children []*Node `json:"children,omitempty"`
+}
+
+// Describe describes the zero value of v's type. v itself is only used
+// for its type; Describe(MyStruct{}) and Describe(&MyStruct{Name: "x"})
+// describe MyStruct and *MyStruct. Use Of for interface types.
+func Describe(v any) *Node {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return &Node{Name: "any", Type: "interface {}", Kind: "interface", Zero: "nil", Nil: true,
+			Note: "nil interface: method calls panic, type assertions fail"}
+	}
+	return DescribeType(t)
+}
+
+// Of describes the zero value of T, which may be an interface type:
+// Of[error]().
+func Of[T any]() *Node {
+	return DescribeType(reflect.TypeFor[T]())
+}
+
+// DescribeType describes the zero value of t.
+func DescribeType(t reflect.Type) *Node {
+	d := &describer{home: home(t), onPath: map[reflect.Type]bool{}}
+	return d.describe(t.String(), t)
+}
+
+// describer walks a type. home is the package whose unexported fields are
+// shown, and onPath holds the types being described further up the tree,
+// to stop at recursive types such as linked lists.
+type describer struct {
+	home   string
+	onPath map[reflect.Type]bool
+}
+
+// home returns the package of the named type at the root of t, behind
+// pointers, slices, arrays, maps and channels. The standard library is
+// never home: its internals are not what a zero value lesson is about.
+func home(t reflect.Type) string {
+	for t.Name() == "" {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+			t = t.Elem()
+		default:
+			return ""
+		}
+	}
+	path := t.PkgPath()
+	if path != "main" && !strings.Contains(strings.Split(path, "/")[0], ".") {
+		return ""
+	}
+	return path
+}
+
+// blocks reports whether the zero value described by c, as a struct field
+// or array element, keeps the containing value from being used as-is.
+func blocks(c *Node) bool {
+	switch c.Kind {
+	case "map", "chan", "struct", "array":
+		return !c.Usable
+	}
+	return false
+}
+
+func (d *describer) describe(name string, t reflect.Type) *Node {
+	n := &Node{Name: name, Type: t.String(), Kind: t.Kind().String(), Usable: true}
+	if d.onPath[t] {
+		n.Zero, n.Note = zeroString(t), "recursive type, described above"
+		n.Nil = nilable(t)
+		n.Usable = !n.Nil
+		return n
+	}
+	d.onPath[t] = true
+	defer delete(d.onPath, t)
+
+	n.Zero = zeroString(t)
+	switch t.Kind() {
+	case reflect.Bool:
+		n.Note = "false"
+	case reflect.String:
+		n.Note = `empty string; len, range, + and comparisons work`
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		// 0 needs no explanation.
+
+	case reflect.Pointer:
+		n.Nil, n.Usable = true, false
+		n.Note = "nil pointer: dereferencing panics; use new(T) or &T{}"
+		n.Children = []*Node{d.describe("*", t.Elem())}
+	case reflect.UnsafePointer:
+		n.Nil, n.Usable = true, false
+		n.Note = "nil pointer"
+	case reflect.Slice:
+		n.Nil = true
+		n.Note = "nil slice: len, cap, range and append work; indexing panics"
+		n.Children = []*Node{d.describe("elem", t.Elem())}
+	case reflect.Map:
+		n.Nil, n.Usable = true, false
+		n.Note = "nil map: reads, len, range and delete work; writing panics; use make"
+		n.Children = []*Node{d.describe("key", t.Key()), d.describe("elem", t.Elem())}
+	case reflect.Chan:
+		n.Nil, n.Usable = true, false
+		n.Note = "nil channel: send and receive block forever, close panics; use make"
+		n.Children = []*Node{d.describe("elem", t.Elem())}
+	case reflect.Func:
+		n.Nil, n.Usable = true, false
+		n.Note = "nil func: calling it panics"
+	case reflect.Interface:
+		n.Nil, n.Usable = true, false
+		n.Note = "nil interface: method calls panic, type assertions fail"
+
+	case reflect.Array:
+		elem := d.describe("[i]", t.Elem())
+		n.Children = []*Node{elem}
+		n.Usable = t.Len() == 0 || !blocks(elem)
+		switch {
+		case t.Len() == 0:
+			n.Note = "empty array"
+		case n.Usable:
+			n.Note = fmt.Sprintf("%d zero %s elements, ready to use", t.Len(), t.Elem())
+		default:
+			n.Note = fmt.Sprintf("%d elements that each need initialising", t.Len())
+		}
+	case reflect.Struct:
+		var blockers, nils []string
+		hidden := 0
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() && t.PkgPath() != d.home {
+				hidden++
+				continue
+			}
+			c := d.describe(f.Name, f.Type)
+			n.Children = append(n.Children, c)
+			switch {
+			case blocks(c):
+				blockers = append(blockers, f.Name)
+			case c.Nil && !c.Usable:
+				nils = append(nils, f.Name)
+			}
+		}
+		n.Usable = len(blockers) == 0
+		var notes []string
+		switch {
+		case t.NumField() == 0:
+			notes = append(notes, "empty struct, takes no memory")
+		case !n.Usable:
+			notes = append(notes, "initialise "+strings.Join(blockers, ", ")+" with make first")
+		case len(nils) > 0:
+			notes = append(notes, "usable as-is; "+strings.Join(nils, ", ")+" stay nil until set, so check before use")
+		case hidden < t.NumField():
+			notes = append(notes, "every field is usable as-is")
+		default:
+			notes = append(notes, "ready to use")
+		}
+		if hidden > 0 {
+			notes = append(notes, fmt.Sprintf("%d unexported field(s) of package %s not shown", hidden, t.PkgPath()))
+		}
+		n.Note = strings.Join(notes, "; ")
+	}
+	return n
+}
+
+func nilable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// zeroString writes the zero value of t in Go syntax, abbreviating
+// structs and arrays to T{}.
+func zeroString(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Array:
+		return t.String() + "{}"
+	case reflect.Func, reflect.Interface:
+		return "nil"
+	case reflect.Float32, reflect.Float64:
+		return "0.0"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "0" // %#v prints unsigned zeros as 0x0
+	}
+	return fmt.Sprintf("%#v", reflect.Zero(t).Interface())
+}
@@ -0,0 +1,100 @@
+package zerovalue
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type registry struct {
+	count int
+	byID  map[int]string
+}
+
+type node struct {
+	Value int
+	Next  *node
+	OnSet func(int)
+}
+
+type withMutex struct {
+	mu    sync.Mutex
+	items []string
+}
+
+type pipeline struct {
+	Stages [2]struct{ Out chan int }
+}
+
+func TestUsable(t *testing.T) {
+	tests := []struct {
+		name   string
+		node   *Node
+		usable bool
+		note   string
+	}{
+		{"sync.Mutex", Describe(sync.Mutex{}), true, "ready to use"},
+		{"bytes.Buffer", Describe(bytes.Buffer{}), true, "ready to use"},
+		{"time.Time", Describe(time.Time{}), true, "ready to use"},
+		{"http.Client", Describe(http.Client{}), true, "Transport, CheckRedirect, Jar stay nil until set"},
+		{"map field", Describe(registry{}), false, "initialise byID with make first"},
+		{"pointer and func fields", Describe(node{}), true, "Next, OnSet stay nil until set"},
+		{"mutex field", Describe(withMutex{}), true, "every field is usable as-is"},
+		{"chan in array", Describe(pipeline{}), false, "initialise Stages with make first"},
+		{"map", Of[map[string]int](), false, "writing panics"},
+		{"pointer", Of[*registry](), false, "dereferencing panics"},
+		{"slice", Of[[]int](), true, "append work"},
+	}
+	for _, tt := range tests {
+		if tt.node.Usable != tt.usable {
+			t.Errorf("%s: Usable = %v, want %v (note %q)", tt.name, tt.node.Usable, tt.usable, tt.node.Note)
+		}
+		if !strings.Contains(tt.node.Note, tt.note) {
+			t.Errorf("%s: Note = %q, want it to contain %q", tt.name, tt.node.Note, tt.note)
+		}
+	}
+}
+
+// Unexported fields of other packages' types are not walked.
+func TestOtherPackages(t *testing.T) {
+	for _, n := range []*Node{Describe(time.Time{}), Describe(sync.Mutex{}), Describe(bytes.Buffer{})} {
+		if len(n.Children) != 0 {
+			t.Errorf("%s: %d children, want the unexported fields hidden", n.Type, len(n.Children))
+		}
+		if !strings.Contains(n.Note, "not shown") {
+			t.Errorf("%s: Note = %q, want it to say fields are not shown", n.Type, n.Note)
+		}
+	}
+	// This package's own unexported fields are shown, a mutex's are not.
+	n := Describe(withMutex{})
+	if len(n.Children) != 2 || len(n.Children[0].Children) != 0 {
+		t.Errorf("withMutex: children %d, mu children %d; want 2 and 0", len(n.Children), len(n.Children[0].Children))
+	}
+}
+
+func TestTree(t *testing.T) {
+	var b strings.Builder
+	if err := Describe(registry{}).WriteTree(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `zerovalue.registry = zerovalue.registry{}  [NOT usable] initialise byID with make first
+├── count int = 0  [usable]
+└── byID map[int]string = map[int]string(nil)  [NOT usable] nil map: reads, len, range and delete work; writing panics; use make
+    ├── key int = 0  [usable]
+    └── elem string = ""  [usable] empty string; len, range, + and comparisons work
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteTree:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRecursive(t *testing.T) {
+	n := Describe(node{})
+	next := n.Children[1].Children[0]
+	if next.Note != "recursive type, described above" {
+		t.Errorf("*node: Note = %q, want the recursion noted", next.Note)
+	}
+}